@@ -0,0 +1,75 @@
+// Package api exposes a running scan's progress and results over HTTP:
+// the current results as JSON, a per-host lookup, a Server-Sent Events
+// stream of discoveries as they happen, and a Prometheus /metrics
+// endpoint. It depends only on report.Document (the same format-agnostic
+// snapshot the report package renders from), never on PDive2's internal,
+// mutex-guarded result types.
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// Event kinds published onto a Bus.
+const (
+	EventHostDiscovered = "host_discovered"
+	EventPortOpen       = "port_open"
+	EventPingRTT        = "ping_rtt"
+)
+
+// Event is a single scan-progress occurrence, published as it happens by
+// the HostDiscovery/PortScan/scanHostPorts worker loops.
+type Event struct {
+	Type    string    `json:"type"`
+	Host    string    `json:"host"`
+	Port    int       `json:"port,omitempty"`
+	Service string    `json:"service,omitempty"`
+	RTT     float64   `json:"rtt_seconds,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Bus fans a stream of Events out to any number of subscribers (the SSE
+// handler, the metrics collector). Publishing never blocks the scan: a
+// subscriber whose channel is full has that event dropped rather than
+// stalling the worker loop that published it.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event]struct{}
+}
+
+// NewBus returns an empty Bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[chan Event]struct{})}
+}
+
+// Publish fans e out to every current subscriber.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new subscriber and returns its event channel and
+// an unsubscribe function. The caller must call unsubscribe when done
+// reading.
+func (b *Bus) Subscribe() (ch chan Event, unsubscribe func()) {
+	ch = make(chan Event, 64)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		close(ch)
+	}
+}
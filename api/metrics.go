@@ -0,0 +1,135 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// rttBuckets are the histogram bucket upper bounds (seconds) for
+// pdive2_ping_rtt_seconds, following Prometheus's usual "le" convention.
+var rttBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects Prometheus counters/gauges/histograms from a Bus's
+// event stream, for rendering by the /metrics endpoint. It keeps no
+// reference to PDive2 itself; everything it knows comes from Events.
+type Metrics struct {
+	start time.Time
+
+	mu                 sync.Mutex
+	hostsDiscovered    int
+	portsOpenByService map[string]int
+	rttBucketCounts    []int // parallel to rttBuckets, cumulative at read time
+	rttSum             float64
+	rttCount           int
+}
+
+// NewMetrics creates a Metrics and starts consuming bus until stop is
+// called.
+func NewMetrics(bus *Bus) *Metrics {
+	m := &Metrics{
+		start:              time.Now(),
+		portsOpenByService: make(map[string]int),
+		rttBucketCounts:    make([]int, len(rttBuckets)),
+	}
+
+	// Metrics subscribes for the lifetime of the process, so it never
+	// calls the returned unsubscribe func.
+	ch, _ := bus.Subscribe()
+	go func() {
+		for e := range ch {
+			m.observe(e)
+		}
+	}()
+
+	return m
+}
+
+func (m *Metrics) observe(e Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch e.Type {
+	case EventHostDiscovered:
+		m.hostsDiscovered++
+	case EventPortOpen:
+		service := e.Service
+		if service == "" {
+			service = "unknown"
+		}
+		m.portsOpenByService[service]++
+	case EventPingRTT:
+		m.rttSum += e.RTT
+		m.rttCount++
+		for i, bound := range rttBuckets {
+			if e.RTT <= bound {
+				m.rttBucketCounts[i]++
+			}
+		}
+	}
+}
+
+// Render writes the current metrics in Prometheus text exposition
+// format, including a live load-average gauge so operators can
+// correlate scan intensity with host load.
+func (m *Metrics) Render(w io.Writer) error {
+	m.mu.Lock()
+	hosts := m.hostsDiscovered
+	services := make([]string, 0, len(m.portsOpenByService))
+	for s := range m.portsOpenByService {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	byService := make(map[string]int, len(m.portsOpenByService))
+	for _, s := range services {
+		byService[s] = m.portsOpenByService[s]
+	}
+	bucketCounts := append([]int(nil), m.rttBucketCounts...)
+	rttSum, rttCount := m.rttSum, m.rttCount
+	m.mu.Unlock()
+
+	var err error
+	p := func(format string, args ...interface{}) {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, format, args...)
+	}
+
+	p("# HELP pdive2_hosts_discovered_total Hosts found to be up during discovery.\n")
+	p("# TYPE pdive2_hosts_discovered_total counter\n")
+	p("pdive2_hosts_discovered_total %d\n", hosts)
+
+	p("# HELP pdive2_ports_open_total Open ports found, labeled by identified service.\n")
+	p("# TYPE pdive2_ports_open_total counter\n")
+	for _, s := range services {
+		p("pdive2_ports_open_total{service=%q} %d\n", s, byService[s])
+	}
+
+	p("# HELP pdive2_scan_duration_seconds Wall-clock time since this scan started.\n")
+	p("# TYPE pdive2_scan_duration_seconds gauge\n")
+	p("pdive2_scan_duration_seconds %f\n", time.Since(m.start).Seconds())
+
+	p("# HELP pdive2_ping_rtt_seconds Observed ICMP ping round-trip time.\n")
+	p("# TYPE pdive2_ping_rtt_seconds histogram\n")
+	for i, bound := range rttBuckets {
+		p("pdive2_ping_rtt_seconds_bucket{le=%q} %d\n", fmt.Sprintf("%g", bound), bucketCounts[i])
+	}
+	p("pdive2_ping_rtt_seconds_bucket{le=\"+Inf\"} %d\n", rttCount)
+	p("pdive2_ping_rtt_seconds_sum %f\n", rttSum)
+	p("pdive2_ping_rtt_seconds_count %d\n", rttCount)
+
+	if avg, loadErr := load.Avg(); loadErr == nil {
+		p("# HELP pdive2_load_average System load average, for correlating scan intensity with host load.\n")
+		p("# TYPE pdive2_load_average gauge\n")
+		p("pdive2_load_average{period=\"1m\"} %f\n", avg.Load1)
+		p("pdive2_load_average{period=\"5m\"} %f\n", avg.Load5)
+		p("pdive2_load_average{period=\"15m\"} %f\n", avg.Load15)
+	}
+
+	return err
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hero2zero/pdive2/log"
+	"github.com/hero2zero/pdive2/report"
+)
+
+// Server is the embedded HTTP/SSE/Prometheus endpoint started by
+// --serve. It holds no reference to PDive2's internal types: the current
+// results are pulled through snapshot on each request, matching the same
+// format-agnostic boundary report.Document already draws for file
+// output.
+type Server struct {
+	addr     string
+	bus      *Bus
+	metrics  *Metrics
+	snapshot func() report.Document
+}
+
+// NewServer returns a Server that will listen on addr once started.
+// snapshot is called on every /api/v1/scan and /api/v1/hosts/{host}
+// request to get the current results.
+func NewServer(addr string, bus *Bus, metrics *Metrics, snapshot func() report.Document) *Server {
+	return &Server{addr: addr, bus: bus, metrics: metrics, snapshot: snapshot}
+}
+
+// ListenAndServe starts the HTTP server. It blocks until the server
+// stops, like http.ListenAndServe.
+func (s *Server) ListenAndServe() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/scan", s.handleScan)
+	mux.HandleFunc("/api/v1/hosts/", s.handleHost)
+	mux.HandleFunc("/api/v1/events", s.handleEvents)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	log.Infof("api", "Serving live scan API on %s", s.addr)
+	return http.ListenAndServe(s.addr, mux)
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.snapshot())
+}
+
+func (s *Server) handleHost(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/hosts/")
+	if name == "" {
+		http.Error(w, "host name required", http.StatusBadRequest)
+		return
+	}
+
+	doc := s.snapshot()
+	for _, h := range doc.Hosts {
+		if h.Host == name {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(h)
+			return
+		}
+	}
+
+	http.Error(w, fmt.Sprintf("host %q not found", name), http.StatusNotFound)
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch, unsubscribe := s.bus.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := s.metrics.Render(w); err != nil {
+		log.Errorf("api", "Failed to write metrics: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Package bruteforce implements opt-in, authenticated credential-stuffing
+// against services identified during enumeration. Each Attempter speaks
+// just enough of one service's auth handshake to test a single
+// username/password pair; a Run invocation fans work out across targets
+// with a concurrency ceiling and per-host lockout backoff.
+package bruteforce
+
+import "time"
+
+// Credential is a single username/password pair to try.
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Target is one service instance to attack, identified by the name a
+// serviceprobe.Probe would report (e.g. "ssh", "mysql").
+type Target struct {
+	Host    string
+	Port    int
+	Service string
+}
+
+// Result records a successful login found by Run.
+type Result struct {
+	Host     string
+	Port     int
+	Service  string
+	Username string
+	Password string
+}
+
+// Attempter tries one credential against one service instance.
+type Attempter interface {
+	// Name is the serviceprobe service name this Attempter handles.
+	Name() string
+	// Try attempts a single login. A returned error means the attempt
+	// itself failed (dial/timeout/protocol error), not that the
+	// credential was rejected - a rejected credential is (false, nil).
+	Try(host string, port int, cred Credential) (bool, error)
+}
+
+var registry = map[string]Attempter{}
+
+// Register adds an Attempter to the set consulted by Run, keyed by the
+// service name it handles.
+func Register(a Attempter) {
+	registry[a.Name()] = a
+}
+
+// Supported reports whether a brute-force Attempter is registered for
+// service.
+func Supported(service string) bool {
+	_, ok := registry[service]
+	return ok
+}
+
+// DefaultTimeout bounds every individual login attempt.
+const DefaultTimeout = 5 * time.Second
@@ -0,0 +1,146 @@
+package bruteforce
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// The handful of BSON encode/decode helpers mongoAttempter needs for a
+// SCRAM-SHA-1 saslStart/saslContinue exchange - not a general BSON
+// library, just the element types those commands and their replies use.
+
+func bsonCString(s string) []byte {
+	return append([]byte(s), 0x00)
+}
+
+func bsonInt32Elem(name string, v int32) []byte {
+	b := append([]byte{0x10}, bsonCString(name)...)
+	val := make([]byte, 4)
+	binary.LittleEndian.PutUint32(val, uint32(v))
+	return append(b, val...)
+}
+
+func bsonStringElem(name, value string) []byte {
+	b := append([]byte{0x02}, bsonCString(name)...)
+	val := make([]byte, 4+len(value)+1)
+	binary.LittleEndian.PutUint32(val[0:4], uint32(len(value)+1))
+	copy(val[4:], value)
+	return append(b, val...)
+}
+
+func bsonBinaryElem(name string, data []byte) []byte {
+	b := append([]byte{0x05}, bsonCString(name)...)
+	val := make([]byte, 4+1+len(data))
+	binary.LittleEndian.PutUint32(val[0:4], uint32(len(data)))
+	val[4] = 0x00 // subtype: generic
+	copy(val[5:], data)
+	return append(b, val...)
+}
+
+// bsonDocument wraps a concatenation of encoded elements with the
+// document's length prefix and terminator.
+func bsonDocument(elems ...[]byte) []byte {
+	var body []byte
+	for _, e := range elems {
+		body = append(body, e...)
+	}
+	body = append(body, 0x00)
+
+	doc := make([]byte, 4+len(body))
+	binary.LittleEndian.PutUint32(doc[0:4], uint32(len(doc)))
+	copy(doc[4:], body)
+	return doc
+}
+
+// bsonField is a single decoded top-level BSON element.
+type bsonField struct {
+	Double float64
+	Int32  int32
+	String string
+	Binary []byte
+	Bool   bool
+}
+
+// bsonDecode parses a top-level BSON document's elements into a map
+// keyed by field name. Embedded documents/arrays are skipped (by their
+// length prefix) rather than recursed into, since none of the fields
+// mongoAttempter reads are nested.
+func bsonDecode(doc []byte) (map[string]bsonField, error) {
+	if len(doc) < 5 {
+		return nil, errors.New("bson document too short")
+	}
+	fields := make(map[string]bsonField)
+
+	i := 4 // skip document length
+	for i < len(doc) && doc[i] != 0x00 {
+		elemType := doc[i]
+		i++
+
+		start := i
+		for i < len(doc) && doc[i] != 0x00 {
+			i++
+		}
+		if i >= len(doc) {
+			return nil, errors.New("unterminated bson element name")
+		}
+		name := string(doc[start:i])
+		i++ // skip name terminator
+
+		switch elemType {
+		case 0x01: // double
+			if i+8 > len(doc) {
+				return nil, errors.New("truncated bson double")
+			}
+			fields[name] = bsonField{Double: math.Float64frombits(binary.LittleEndian.Uint64(doc[i : i+8]))}
+			i += 8
+		case 0x02: // string
+			if i+4 > len(doc) {
+				return nil, errors.New("truncated bson string length")
+			}
+			strLen := int(binary.LittleEndian.Uint32(doc[i : i+4]))
+			i += 4
+			if i+strLen > len(doc) {
+				return nil, errors.New("truncated bson string")
+			}
+			fields[name] = bsonField{String: string(doc[i : i+strLen-1])}
+			i += strLen
+		case 0x03, 0x04: // embedded document or array: skip by its own length prefix
+			if i+4 > len(doc) {
+				return nil, errors.New("truncated bson subdocument length")
+			}
+			subLen := int(binary.LittleEndian.Uint32(doc[i : i+4]))
+			i += subLen
+		case 0x05: // binary
+			if i+5 > len(doc) {
+				return nil, errors.New("truncated bson binary header")
+			}
+			binLen := int(binary.LittleEndian.Uint32(doc[i : i+4]))
+			i += 5 // length + subtype
+			if i+binLen > len(doc) {
+				return nil, errors.New("truncated bson binary data")
+			}
+			fields[name] = bsonField{Binary: doc[i : i+binLen]}
+			i += binLen
+		case 0x08: // boolean
+			if i >= len(doc) {
+				return nil, errors.New("truncated bson boolean")
+			}
+			fields[name] = bsonField{Bool: doc[i] != 0}
+			i++
+		case 0x0a: // null
+		case 0x10: // int32
+			if i+4 > len(doc) {
+				return nil, errors.New("truncated bson int32")
+			}
+			fields[name] = bsonField{Int32: int32(binary.LittleEndian.Uint32(doc[i : i+4]))}
+			i += 4
+		case 0x12: // int64
+			i += 8
+		default:
+			return nil, errors.New("unsupported bson element type in mongo reply")
+		}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,45 @@
+package bruteforce
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() { Register(ftpAttempter{}) }
+
+type ftpAttempter struct{}
+
+func (ftpAttempter) Name() string { return "ftp" }
+
+// Try logs in with USER/PASS and checks for a 230 (logged in) reply.
+func (ftpAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := readLine(conn, DefaultTimeout); err != nil {
+		return false, err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := fmt.Fprintf(conn, "USER %s\r\n", cred.Username); err != nil {
+		return false, err
+	}
+	if _, err := readLine(conn, DefaultTimeout); err != nil {
+		return false, err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := fmt.Fprintf(conn, "PASS %s\r\n", cred.Password); err != nil {
+		return false, err
+	}
+	reply, err := readLine(conn, DefaultTimeout)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.HasPrefix(reply, "230"), nil
+}
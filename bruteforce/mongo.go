@@ -0,0 +1,221 @@
+package bruteforce
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func init() { Register(mongoAttempter{}) }
+
+type mongoAttempter struct{}
+
+func (mongoAttempter) Name() string { return "mongodb" }
+
+// Try runs a full SCRAM-SHA-1 saslStart/saslContinue exchange over
+// OP_MSG, the mechanism every MongoDB deployment with auth enabled
+// supports regardless of server version.
+func (mongoAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	clientNonce := mongoNonce()
+	gs2Header := "n,,"
+	clientFirstBare := "n=" + mongoSaslEscape(cred.Username) + ",r=" + clientNonce
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	startCmd := bsonDocument(
+		bsonInt32Elem("saslStart", 1),
+		bsonStringElem("mechanism", "SCRAM-SHA-1"),
+		bsonBinaryElem("payload", []byte(gs2Header+clientFirstBare)),
+		bsonInt32Elem("autoAuthorize", 1),
+		bsonStringElem("$db", "admin"),
+	)
+	if _, err := conn.Write(opMsg(startCmd)); err != nil {
+		return false, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	reply, err := readOpMsgDocument(conn)
+	if err != nil {
+		return false, err
+	}
+	if reply["ok"].Double != 1 {
+		return false, nil
+	}
+
+	conversationID := reply["conversationId"].Int32
+	serverFirst := string(reply["payload"].Binary)
+
+	params, err := parseSCRAMMessage(serverFirst)
+	if err != nil {
+		return false, err
+	}
+	serverNonce, salt, iterations := params["r"], params["s"], params["i"]
+	if !strings.HasPrefix(serverNonce, clientNonce) {
+		return false, errors.New("mongo SCRAM server nonce does not extend client nonce")
+	}
+	iterCount, err := strconv.Atoi(iterations)
+	if err != nil {
+		return false, fmt.Errorf("invalid mongo SCRAM iteration count: %w", err)
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return false, err
+	}
+
+	clientFinalWithoutProof := "c=" + base64.StdEncoding.EncodeToString([]byte(gs2Header)) + ",r=" + serverNonce
+	authMessage := clientFirstBare + "," + serverFirst + "," + clientFinalWithoutProof
+
+	hashedPassword := mongoPasswordDigest(cred.Username, cred.Password)
+	saltedPassword := pbkdf2.Key([]byte(hashedPassword), saltBytes, iterCount, sha1.Size, sha1.New)
+
+	clientKey := hmacSHA1(saltedPassword, "Client Key")
+	storedKey := sha1.Sum(clientKey)
+	clientSignature := hmacSHA1(storedKey[:], authMessage)
+	clientProof := xorBytes(clientKey, clientSignature)
+
+	clientFinal := clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(clientProof)
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	continueCmd := bsonDocument(
+		bsonInt32Elem("saslContinue", 1),
+		bsonInt32Elem("conversationId", conversationID),
+		bsonBinaryElem("payload", []byte(clientFinal)),
+		bsonStringElem("$db", "admin"),
+	)
+	if _, err := conn.Write(opMsg(continueCmd)); err != nil {
+		return false, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	reply, err = readOpMsgDocument(conn)
+	if err != nil {
+		return false, err
+	}
+	if reply["ok"].Double != 1 {
+		return false, nil
+	}
+
+	if !reply["done"].Bool {
+		// MongoDB's SCRAM exchange requires one final empty continuation
+		// to mark the server as satisfied.
+		conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+		finalCmd := bsonDocument(
+			bsonInt32Elem("saslContinue", 1),
+			bsonInt32Elem("conversationId", conversationID),
+			bsonBinaryElem("payload", []byte{}),
+			bsonStringElem("$db", "admin"),
+		)
+		if _, err := conn.Write(opMsg(finalCmd)); err != nil {
+			return false, err
+		}
+		conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+		reply, err = readOpMsgDocument(conn)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return reply["ok"].Double == 1, nil
+}
+
+// mongoPasswordDigest is the SCRAM-SHA-1 "mechanism-specific" password
+// hash MongoDB has used since the MONGODB-CR days: hex(md5(user + ":mongo:" + password)).
+func mongoPasswordDigest(user, password string) string {
+	sum := md5.Sum([]byte(user + ":mongo:" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+func mongoSaslEscape(s string) string {
+	s = strings.ReplaceAll(s, "=", "=3D")
+	s = strings.ReplaceAll(s, ",", "=2C")
+	return s
+}
+
+func mongoNonce() string {
+	buf := make([]byte, 18)
+	rand.Read(buf)
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+func hmacSHA1(key []byte, msg string) []byte {
+	h := hmac.New(sha1.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range out {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// parseSCRAMMessage splits a comma-separated "k=v,k=v" SCRAM message into
+// a map of its attributes.
+func parseSCRAMMessage(msg string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	for _, part := range strings.Split(msg, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed SCRAM attribute: %q", part)
+		}
+		attrs[k] = v
+	}
+	return attrs, nil
+}
+
+// opMsg frames a BSON command document as an OP_MSG request.
+func opMsg(doc []byte) []byte {
+	var msg []byte
+	msg = append(msg, make([]byte, 16)...)         // header, patched below
+	msg = binary.LittleEndian.AppendUint32(msg, 0) // flagBits
+	msg = append(msg, 0x00)                        // kind: body
+	msg = append(msg, doc...)
+
+	binary.LittleEndian.PutUint32(msg[0:4], uint32(len(msg)))
+	binary.LittleEndian.PutUint32(msg[4:8], 1)
+	binary.LittleEndian.PutUint32(msg[8:12], 0)
+	binary.LittleEndian.PutUint32(msg[12:16], 2013) // opCode: OP_MSG
+	return msg
+}
+
+// readOpMsgDocument reads one OP_MSG reply and decodes its single body
+// document.
+func readOpMsgDocument(conn interface{ Read([]byte) (int, error) }) (map[string]bsonField, error) {
+	header := make([]byte, 16)
+	if _, err := readFullBytes(conn, header); err != nil {
+		return nil, err
+	}
+	msgLen := binary.LittleEndian.Uint32(header[0:4])
+	opCode := binary.LittleEndian.Uint32(header[12:16])
+	if opCode != 2013 || msgLen < 21 {
+		return nil, errors.New("unexpected mongo response opcode")
+	}
+
+	body := make([]byte, msgLen-16)
+	if _, err := readFullBytes(conn, body); err != nil {
+		return nil, err
+	}
+	if len(body) < 5 || body[4] != 0x00 {
+		return nil, errors.New("unexpected mongo OP_MSG section kind")
+	}
+
+	return bsonDecode(body[5:])
+}
@@ -0,0 +1,211 @@
+package bruteforce
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+	"unicode/utf16"
+)
+
+func init() { Register(mssqlAttempter{}) }
+
+type mssqlAttempter struct{}
+
+func (mssqlAttempter) Name() string { return "mssql" }
+
+// Try completes the TDS PRELOGIN handshake (declining encryption, same as
+// serviceprobe's mssql probe) and then sends a LOGIN7 packet authenticating
+// with SQL Server auth (not Windows/NTLM). A LOGINACK token in the
+// response means the credential was accepted; an ERROR token means it
+// was rejected.
+func (mssqlAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(tdsPacket(0x12, buildPrelogin())); err != nil {
+		return false, err
+	}
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := readTDSResponse(conn); err != nil {
+		return false, err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(tdsPacket(0x10, buildLogin7(host, cred.Username, cred.Password))); err != nil {
+		return false, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	resp, err := readTDSResponse(conn)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.IndexByte(resp, 0xad) >= 0, nil // 0xad == LOGINACK token
+}
+
+// tdsPacket wraps payload in a single TDS packet header (type, status
+// EOM, length, SPID, packet id, window).
+func tdsPacket(packetType byte, payload []byte) []byte {
+	header := []byte{packetType, 0x01, 0, 0, 0, 0, 0x01, 0x00}
+	binary.BigEndian.PutUint16(header[2:4], uint16(len(header)+len(payload)))
+	return append(header, payload...)
+}
+
+func buildPrelogin() []byte {
+	const (
+		tokenVersion    = 0x00
+		tokenEncryption = 0x01
+		tokenTerminator = 0xff
+	)
+
+	versionData := []byte{0, 0, 0, 0, 0, 0}
+	encryptionData := []byte{0x02} // ENCRYPT_NOT_SUP
+
+	tokenTableLen := 2*5 + 1
+	payload := make([]byte, 0, tokenTableLen+len(versionData)+len(encryptionData))
+
+	versionOff := tokenTableLen
+	encryptionOff := versionOff + len(versionData)
+
+	payload = append(payload, tokenVersion)
+	payload = binary.BigEndian.AppendUint16(payload, uint16(versionOff))
+	payload = binary.BigEndian.AppendUint16(payload, uint16(len(versionData)))
+
+	payload = append(payload, tokenEncryption)
+	payload = binary.BigEndian.AppendUint16(payload, uint16(encryptionOff))
+	payload = binary.BigEndian.AppendUint16(payload, uint16(len(encryptionData)))
+
+	payload = append(payload, tokenTerminator)
+	payload = append(payload, versionData...)
+	payload = append(payload, encryptionData...)
+	return payload
+}
+
+// buildLogin7 assembles a TDS 7.1 LOGIN7 packet authenticating with SQL
+// Server auth: OptionFlags2's fIntSecurity bit stays clear, and the
+// password travels obfuscated per MS-TDS (nibble-swapped, then XORed
+// with 0xA5), never in the clear.
+func buildLogin7(server, user, password string) []byte {
+	hostname := ucs2("")
+	username := ucs2(user)
+	obfPassword := obfuscateTDSPassword(password)
+	appName := ucs2("pdive2")
+	serverName := ucs2(server)
+	cltIntName := ucs2("ODBC")
+	language := ucs2("")
+	database := ucs2("")
+
+	const fixedLen = 4 + 4 + 4 + 4 + 4 + 1 + 1 + 1 + 1 + 4 + 4
+	const offsetTableLen = 2*2*9 + 6 // 9 offset/length pairs + 6-byte ClientID
+
+	var data bytes.Buffer
+	data.Write(hostname)
+	data.Write(username)
+	data.Write(obfPassword)
+	data.Write(appName)
+	data.Write(serverName)
+	data.Write(cltIntName)
+	data.Write(language)
+	data.Write(database)
+
+	fieldOffset := uint16(4 + fixedLen + offsetTableLen)
+
+	var body bytes.Buffer
+	binary.Write(&body, binary.LittleEndian, uint32(0x71000001)) // TDSVersion 7.1
+	binary.Write(&body, binary.LittleEndian, uint32(4096))       // PacketSize
+	binary.Write(&body, binary.LittleEndian, uint32(0))          // ClientProgVer
+	binary.Write(&body, binary.LittleEndian, uint32(0))          // ClientPID
+	binary.Write(&body, binary.LittleEndian, uint32(0))          // ConnectionID
+	body.WriteByte(0x00)                                         // OptionFlags1
+	body.WriteByte(0x00)                                         // OptionFlags2 (fIntSecurity clear: SQL auth)
+	body.WriteByte(0x00)                                         // TypeFlags
+	body.WriteByte(0x00)                                         // OptionFlags3 / reserved
+	binary.Write(&body, binary.LittleEndian, uint32(0))          // ClientTimeZone
+	binary.Write(&body, binary.LittleEndian, uint32(0))          // ClientLCID
+
+	off := fieldOffset
+	writeOffsetLen := func(field []byte) {
+		binary.Write(&body, binary.LittleEndian, off)
+		binary.Write(&body, binary.LittleEndian, uint16(len(field)/2))
+		off += uint16(len(field))
+	}
+	writeOffsetLen(hostname)
+	writeOffsetLen(username)
+	writeOffsetLen(obfPassword)
+	writeOffsetLen(appName)
+	writeOffsetLen(serverName)
+	binary.Write(&body, binary.LittleEndian, uint16(0)) // ibExtension
+	binary.Write(&body, binary.LittleEndian, uint16(0)) // cbExtension
+	writeOffsetLen(cltIntName)
+	writeOffsetLen(language)
+	writeOffsetLen(database)
+	body.Write(make([]byte, 6))                         // ClientID
+	binary.Write(&body, binary.LittleEndian, off)       // ibSSPI
+	binary.Write(&body, binary.LittleEndian, uint16(0)) // cchSSPI
+	binary.Write(&body, binary.LittleEndian, off)       // ibAtchDBFile
+	binary.Write(&body, binary.LittleEndian, uint16(0)) // cchAtchDBFile
+
+	body.Write(data.Bytes())
+
+	full := make([]byte, 4, 4+body.Len())
+	binary.LittleEndian.PutUint32(full[0:4], uint32(4+body.Len()))
+	full = append(full, body.Bytes()...)
+	return full
+}
+
+// ucs2 encodes s as little-endian UTF-16, the string encoding MS-TDS
+// requires for every LOGIN7 text field.
+func ucs2(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	buf := make([]byte, len(units)*2)
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(buf[i*2:], u)
+	}
+	return buf
+}
+
+// obfuscateTDSPassword applies MS-TDS's password obfuscation: UCS-2
+// encode, swap each byte's nibbles, then XOR with 0xA5.
+func obfuscateTDSPassword(password string) []byte {
+	buf := ucs2(password)
+	for i, b := range buf {
+		swapped := (b << 4) | (b >> 4)
+		buf[i] = swapped ^ 0xa5
+	}
+	return buf
+}
+
+// readTDSResponse reads TDS response packets until one with the EOM
+// status bit set, returning the concatenated payload.
+func readTDSResponse(conn interface{ Read([]byte) (int, error) }) ([]byte, error) {
+	var payload []byte
+	for {
+		header := make([]byte, 8)
+		if _, err := readFullBytes(conn, header); err != nil {
+			return nil, err
+		}
+		length := int(binary.BigEndian.Uint16(header[2:4]))
+		if length < 8 {
+			return nil, errors.New("malformed TDS packet length")
+		}
+
+		body := make([]byte, length-8)
+		if len(body) > 0 {
+			if _, err := readFullBytes(conn, body); err != nil {
+				return nil, err
+			}
+		}
+		payload = append(payload, body...)
+
+		if header[1]&0x01 != 0 { // EOM
+			break
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,174 @@
+package bruteforce
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+func init() { Register(mysqlAttempter{}) }
+
+type mysqlAttempter struct{}
+
+func (mysqlAttempter) Name() string { return "mysql" }
+
+// Try reads the server's v10 handshake, derives the mysql_native_password
+// scramble from the handshake's auth-plugin-data seed, and sends a
+// HandshakeResponse41. An OK packet means the credential was accepted; an
+// ERR packet means it was rejected.
+func (mysqlAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	handshake, err := readMySQLPacket(conn)
+	if err != nil {
+		return false, err
+	}
+
+	seed, err := parseMySQLSeed(handshake)
+	if err != nil {
+		return false, err
+	}
+
+	response := buildHandshakeResponse41(cred.Username, cred.Password, seed)
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(mysqlPacket(response, 1)); err != nil {
+		return false, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	reply, err := readMySQLPacket(conn)
+	if err != nil {
+		return false, err
+	}
+	if len(reply) == 0 {
+		return false, errors.New("empty mysql auth reply")
+	}
+
+	return reply[0] == 0x00, nil
+}
+
+// readMySQLPacket reads one length-prefixed MySQL protocol packet and
+// returns its payload (the header's 3-byte length + 1-byte sequence id
+// are consumed, not returned).
+func readMySQLPacket(conn interface{ Read([]byte) (int, error) }) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := readFullBytes(conn, header); err != nil {
+		return nil, err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+
+	payload := make([]byte, length)
+	if length > 0 {
+		if _, err := readFullBytes(conn, payload); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+func readFullBytes(conn interface{ Read([]byte) (int, error) }, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// mysqlPacket wraps payload in a MySQL packet header with the given
+// sequence id.
+func mysqlPacket(payload []byte, seq byte) []byte {
+	header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}
+	return append(header, payload...)
+}
+
+// parseMySQLSeed extracts the 20-byte auth-plugin-data seed from a v10
+// handshake packet (8-byte part 1, then a 12-byte part 2 after the fixed
+// capability/charset/status fields).
+func parseMySQLSeed(handshake []byte) ([]byte, error) {
+	if len(handshake) < 1 || handshake[0] != 0x0a {
+		return nil, errors.New("unsupported mysql protocol version")
+	}
+
+	i := 1
+	end := bytes.IndexByte(handshake[i:], 0x00)
+	if end < 0 {
+		return nil, errors.New("malformed mysql handshake: no server version terminator")
+	}
+	i += end + 1 // skip server version + NUL
+
+	if len(handshake) < i+8+1+2+1+2+2+1+10+12 {
+		return nil, errors.New("malformed mysql handshake: too short")
+	}
+
+	seed := make([]byte, 0, 20)
+	seed = append(seed, handshake[i:i+8]...) // auth-plugin-data-part-1
+	i += 8
+	i += 1 + 2 + 1 + 2 + 2 + 1 + 10 // filler, caps-1, charset, status, caps-2, auth-data-len, reserved
+
+	seed = append(seed, handshake[i:i+12]...) // auth-plugin-data-part-2, minus its trailing NUL
+	return seed, nil
+}
+
+// buildHandshakeResponse41 builds a HandshakeResponse41 authenticating
+// with the mysql_native_password plugin.
+func buildHandshakeResponse41(user, password string, seed []byte) []byte {
+	const (
+		clientProtocol41   = 0x00000200
+		clientSecureConn   = 0x00008000
+		clientPluginAuth   = 0x00080000
+		clientLongPassword = 0x00000001
+		clientTransactions = 0x00002000
+	)
+	capabilities := uint32(clientProtocol41 | clientSecureConn | clientPluginAuth | clientLongPassword | clientTransactions)
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, capabilities)
+	binary.Write(&buf, binary.LittleEndian, uint32(16777216)) // max packet size
+	buf.WriteByte(33)                                         // charset: utf8_general_ci
+	buf.Write(make([]byte, 23))                               // reserved
+	buf.WriteString(user)
+	buf.WriteByte(0x00)
+
+	scramble := mysqlNativePasswordScramble(password, seed)
+	buf.WriteByte(byte(len(scramble)))
+	buf.Write(scramble)
+
+	buf.WriteString("mysql_native_password")
+	buf.WriteByte(0x00)
+
+	return buf.Bytes()
+}
+
+// mysqlNativePasswordScramble implements the mysql_native_password
+// algorithm: SHA1(password) XOR SHA1(seed + SHA1(SHA1(password))).
+func mysqlNativePasswordScramble(password string, seed []byte) []byte {
+	if password == "" {
+		return nil
+	}
+
+	stage1 := sha1.Sum([]byte(password))
+	stage2 := sha1.Sum(stage1[:])
+
+	h := sha1.New()
+	h.Write(seed)
+	h.Write(stage2[:])
+	stage3 := h.Sum(nil)
+
+	token := make([]byte, len(stage3))
+	for i := range token {
+		token[i] = stage3[i] ^ stage1[i]
+	}
+	return token
+}
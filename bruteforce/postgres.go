@@ -0,0 +1,131 @@
+package bruteforce
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+func init() { Register(postgresAttempter{}) }
+
+type postgresAttempter struct{}
+
+func (postgresAttempter) Name() string { return "postgresql" }
+
+// Try sends a StartupMessage and answers whichever AuthenticationRequest
+// the server comes back with (cleartext or MD5 are the two password
+// methods a default install offers).
+func (postgresAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(buildStartupMessage(cred.Username)); err != nil {
+		return false, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	msgType, payload, err := readPostgresMessage(conn)
+	if err != nil {
+		return false, err
+	}
+	if msgType == 'E' {
+		return false, nil
+	}
+	if msgType != 'R' || len(payload) < 4 {
+		return false, errors.New("unexpected postgres response to startup")
+	}
+
+	authType := binary.BigEndian.Uint32(payload[0:4])
+	switch authType {
+	case 0: // AuthenticationOk, no password required
+		return true, nil
+	case 3: // AuthenticationCleartextPassword
+		if _, err := conn.Write(passwordMessage(cred.Password)); err != nil {
+			return false, err
+		}
+	case 5: // AuthenticationMD5Password
+		if len(payload) < 8 {
+			return false, errors.New("malformed AuthenticationMD5Password")
+		}
+		salt := payload[4:8]
+		hashed := postgresMD5Password(cred.Username, cred.Password, salt)
+		if _, err := conn.Write(passwordMessage(hashed)); err != nil {
+			return false, err
+		}
+	default:
+		return false, errors.New("unsupported postgres auth method")
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	msgType, payload, err = readPostgresMessage(conn)
+	if err != nil {
+		return false, err
+	}
+	if msgType == 'E' {
+		return false, nil
+	}
+	return msgType == 'R' && len(payload) >= 4 && binary.BigEndian.Uint32(payload[0:4]) == 0, nil
+}
+
+func buildStartupMessage(user string) []byte {
+	var params []byte
+	params = append(params, []byte("user")...)
+	params = append(params, 0x00)
+	params = append(params, []byte(user)...)
+	params = append(params, 0x00)
+	params = append(params, 0x00) // terminator
+
+	msg := make([]byte, 8, 8+len(params))
+	binary.BigEndian.PutUint32(msg[4:8], 0x00030000) // protocol version 3.0
+	msg = append(msg, params...)
+	binary.BigEndian.PutUint32(msg[0:4], uint32(len(msg)))
+	return msg
+}
+
+func passwordMessage(password string) []byte {
+	body := append([]byte(password), 0x00)
+	msg := make([]byte, 0, 5+len(body))
+	msg = append(msg, 'p')
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(4+len(body)))
+	msg = append(msg, length...)
+	msg = append(msg, body...)
+	return msg
+}
+
+// postgresMD5Password computes "md5" + md5hex(md5hex(password+user) + salt),
+// the format Postgres expects in response to AuthenticationMD5Password.
+func postgresMD5Password(user, password string, salt []byte) string {
+	inner := md5.Sum([]byte(password + user))
+	innerHex := hex.EncodeToString(inner[:])
+
+	outer := md5.Sum(append([]byte(innerHex), salt...))
+	return "md5" + hex.EncodeToString(outer[:])
+}
+
+// readPostgresMessage reads one Postgres backend message: a 1-byte type,
+// a 4-byte length (including itself), and the remaining payload.
+func readPostgresMessage(conn interface{ Read([]byte) (int, error) }) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := readFullBytes(conn, header); err != nil {
+		return 0, nil, err
+	}
+	length := binary.BigEndian.Uint32(header[1:5])
+	if length < 4 {
+		return 0, nil, errors.New("malformed postgres message length")
+	}
+
+	payload := make([]byte, length-4)
+	if len(payload) > 0 {
+		if _, err := readFullBytes(conn, payload); err != nil {
+			return 0, nil, err
+		}
+	}
+	return header[0], payload, nil
+}
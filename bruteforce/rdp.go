@@ -0,0 +1,78 @@
+package bruteforce
+
+import (
+	"errors"
+	"time"
+)
+
+func init() { Register(rdpAttempter{}) }
+
+type rdpAttempter struct{}
+
+func (rdpAttempter) Name() string { return "rdp" }
+
+// Try negotiates the RDP security layer and, for servers still offering
+// legacy standard RDP security, is a placeholder for a future Client
+// Info PDU credential exchange. Every server seen in practice today
+// requires CredSSP (NLA), whose credential exchange runs inside a
+// SPNEGO/NTLM handshake over TLS - out of scope for this pass - so Try
+// reports that explicitly rather than claiming a result it can't back.
+func (rdpAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(x224ConnectionRequest()); err != nil {
+		return false, err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	if err != nil || n < 19 {
+		return false, err
+	}
+	resp := buf[:n]
+	if resp[5] != 0xd0 {
+		return false, errors.New("rdp: server did not confirm X.224 connection")
+	}
+
+	selected := resp[15]
+	if selected == 0x01 || selected == 0x02 || selected == 0x03 {
+		return false, errors.New("rdp: server requires TLS/CredSSP (NLA); credential exchange over NLA is not implemented")
+	}
+
+	return false, errors.New("rdp: standard security credential exchange is not implemented")
+}
+
+// x224ConnectionRequest builds a TPKT-framed X.224 Connection Request
+// with an embedded RDP_NEG_REQ advertising PROTOCOL_SSL | PROTOCOL_HYBRID,
+// matching serviceprobe's RDP probe.
+func x224ConnectionRequest() []byte {
+	negReq := []byte{
+		0x01,       // type: RDP_NEG_REQ
+		0x00,       // flags
+		0x08, 0x00, // length (LE) = 8
+		0x03, 0x00, 0x00, 0x00, // requestedProtocols: SSL | HYBRID (LE)
+	}
+
+	x224 := []byte{
+		0x00,       // length indicator, patched below
+		0xe0,       // CR TPDU code
+		0x00, 0x00, // dst-ref
+		0x00, 0x00, // src-ref
+		0x00, // class/options
+	}
+	x224 = append(x224, negReq...)
+	x224[0] = byte(len(x224) - 1)
+
+	tpkt := []byte{0x03, 0x00, 0x00, 0x00}
+	full := append(tpkt, x224...)
+	full[2] = byte(len(full) >> 8)
+	full[3] = byte(len(full))
+
+	return full
+}
@@ -0,0 +1,42 @@
+package bruteforce
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() { Register(redisAttempter{}) }
+
+type redisAttempter struct{}
+
+func (redisAttempter) Name() string { return "redis" }
+
+// Try issues an inline AUTH command. Redis 6+ ACL accounts use
+// "AUTH user pass"; a legacy requirepass server only understands
+// "AUTH pass" and returns an error for the two-argument form, so an
+// empty username falls back to it.
+func (redisAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	conn, err := dial(host, port)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	cmd := fmt.Sprintf("AUTH %s\r\n", cred.Password)
+	if cred.Username != "" {
+		cmd = fmt.Sprintf("AUTH %s %s\r\n", cred.Username, cred.Password)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write([]byte(cmd)); err != nil {
+		return false, err
+	}
+
+	reply, err := readLine(conn, DefaultTimeout)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.HasPrefix(reply, "+OK"), nil
+}
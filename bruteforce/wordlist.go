@@ -0,0 +1,66 @@
+package bruteforce
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadLines reads path and returns its non-empty, non-comment lines, used
+// for both --users and --passwords wordlists.
+func LoadLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wordlist %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist %s: %w", path, err)
+	}
+
+	return lines, nil
+}
+
+// LoadUserPass reads a --userpass combo file of "user:pass" lines, one
+// credential per line.
+func LoadUserPass(path string) ([]Credential, error) {
+	lines, err := LoadLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]Credential, 0, len(lines))
+	for _, line := range lines {
+		user, pass, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid combo line (want user:pass): %q", line)
+		}
+		creds = append(creds, Credential{Username: user, Password: pass})
+	}
+
+	return creds, nil
+}
+
+// BuildCredentials assembles the credential set to try: the cartesian
+// product of users x passwords, plus any explicit user:pass combos.
+func BuildCredentials(users, passwords []string, combos []Credential) []Credential {
+	creds := make([]Credential, 0, len(users)*len(passwords)+len(combos))
+	for _, u := range users {
+		for _, p := range passwords {
+			creds = append(creds, Credential{Username: u, Password: p})
+		}
+	}
+	creds = append(creds, combos...)
+	return creds
+}
@@ -0,0 +1,175 @@
+// Package discovery implements local-link host discovery for hosts that
+// don't answer ICMP or TCP-connect probes: active mDNS/DNS-SD service
+// browsing and NetBIOS name service (NBSTAT) queries.
+package discovery
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// mDNS well-known addresses (RFC 6762).
+const (
+	mdnsIPv4Addr = "224.0.0.251:5353"
+	mdnsIPv6Addr = "[ff02::fb]:5353"
+)
+
+// serviceTypes are the DNS-SD service types queried in addition to the
+// generic "list all services" meta-query.
+var serviceTypes = []string{
+	"_services._dns-sd._udp.local.",
+	"_http._tcp.local.",
+	"_ssh._tcp.local.",
+	"_workstation._tcp.local.",
+	"_smb._tcp.local.",
+}
+
+// Host is a host discovered via mDNS/DNS-SD, enriched with whatever
+// hostname and service information was advertised.
+type Host struct {
+	Address  string
+	Hostname string
+	Services []string
+}
+
+// BrowseMDNS sends DNS-SD queries for serviceTypes over multicast and
+// collects answers for dwell before returning. It degrades gracefully
+// when IPv6 multicast isn't available or the process is unprivileged:
+// failures on one address family are logged by the caller and simply
+// yield fewer results rather than aborting the browse.
+func BrowseMDNS(dwell time.Duration) ([]Host, error) {
+	query, err := buildQuery(serviceTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := map[string]*Host{}
+	addrsByName := map[string][]string{}
+	servicesByInstance := map[string]string{}
+	instanceTarget := map[string]string{}
+
+	collect := func(network, mcastAddr string) {
+		conn, err := net.ListenPacket(network, ":5353")
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		dst, err := net.ResolveUDPAddr(network, mcastAddr)
+		if err != nil {
+			return
+		}
+		conn.WriteTo(query, dst)
+
+		deadline := time.Now().Add(dwell)
+		buf := make([]byte, 8192)
+		for {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return
+			}
+			conn.SetReadDeadline(time.Now().Add(remaining))
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			parseResponse(buf[:n], addrsByName, servicesByInstance, instanceTarget)
+		}
+	}
+
+	collect("udp4", mdnsIPv4Addr)
+	collect("udp6", mdnsIPv6Addr)
+
+	// Resolve service instances -> SRV target -> addresses.
+	for instance, target := range instanceTarget {
+		service := servicesByInstance[instance]
+		for _, addr := range addrsByName[target] {
+			host := hosts[addr]
+			if host == nil {
+				host = &Host{Address: addr, Hostname: strings.TrimSuffix(target, ".")}
+				hosts[addr] = host
+			}
+			if service != "" {
+				host.Services = append(host.Services, service)
+			}
+		}
+	}
+
+	// Also surface bare A/AAAA answers that never resolved to a service,
+	// so a host with no registered services still shows up.
+	for name, addrs := range addrsByName {
+		for _, addr := range addrs {
+			if _, exists := hosts[addr]; !exists {
+				hosts[addr] = &Host{Address: addr, Hostname: strings.TrimSuffix(name, ".")}
+			}
+		}
+	}
+
+	result := make([]Host, 0, len(hosts))
+	for _, h := range hosts {
+		result = append(result, *h)
+	}
+	return result, nil
+}
+
+func buildQuery(names []string) ([]byte, error) {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+
+	for _, n := range names {
+		name, err := dnsmessage.NewName(n)
+		if err != nil {
+			return nil, err
+		}
+		q := dnsmessage.Question{
+			Name:  name,
+			Type:  dnsmessage.TypePTR,
+			Class: dnsmessage.ClassINET,
+		}
+		if err := builder.Question(q); err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.Finish()
+}
+
+// parseResponse pulls PTR/SRV/A/AAAA records out of an mDNS response and
+// folds them into the running name -> address and instance -> target maps.
+func parseResponse(msg []byte, addrsByName map[string][]string, servicesByInstance, instanceTarget map[string]string) {
+	var m dnsmessage.Message
+	if err := m.Unpack(msg); err != nil {
+		return
+	}
+
+	for _, rr := range append(append([]dnsmessage.Resource{}, m.Answers...), m.Additionals...) {
+		name := rr.Header.Name.String()
+		switch body := rr.Body.(type) {
+		case *dnsmessage.PTRResource:
+			instance := body.PTR.String()
+			servicesByInstance[instance] = strings.TrimSuffix(name, ".")
+		case *dnsmessage.SRVResource:
+			instanceTarget[name] = body.Target.String()
+		case *dnsmessage.AResource:
+			ip := net.IP(body.A[:]).String()
+			addrsByName[name] = appendUnique(addrsByName[name], ip)
+		case *dnsmessage.AAAAResource:
+			ip := net.IP(body.AAAA[:]).String()
+			addrsByName[name] = appendUnique(addrsByName[name], ip)
+		}
+	}
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// NetBIOSInfo is the result of an NBSTAT (node status) query against a
+// single host on UDP/137.
+type NetBIOSInfo struct {
+	Names []string
+	MAC   string
+}
+
+// QueryNetBIOS sends an NBSTAT request to host:137 and parses the reply.
+// It's unicast, so it works against unprivileged sockets and doesn't
+// require the local segment to support multicast.
+func QueryNetBIOS(host string, timeout time.Duration) (NetBIOSInfo, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(host, "137"), timeout)
+	if err != nil {
+		return NetBIOSInfo{}, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(nbstatRequest()); err != nil {
+		return NetBIOSInfo{}, err
+	}
+
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return NetBIOSInfo{}, err
+	}
+
+	return parseNBSTATResponse(buf[:n])
+}
+
+// nbstatRequest builds an RFC 1002 NBSTAT query for the wildcard name
+// "*", the standard way to ask a host "who are you".
+func nbstatRequest() []byte {
+	var buf []byte
+
+	// Header: transaction ID, flags (standard query), 1 question.
+	buf = binary.BigEndian.AppendUint16(buf, 0x1337) // transaction ID
+	buf = binary.BigEndian.AppendUint16(buf, 0x0000) // flags: query
+	buf = binary.BigEndian.AppendUint16(buf, 1)      // QDCOUNT
+	buf = binary.BigEndian.AppendUint16(buf, 0)      // ANCOUNT
+	buf = binary.BigEndian.AppendUint16(buf, 0)      // NSCOUNT
+	buf = binary.BigEndian.AppendUint16(buf, 0)      // ARCOUNT
+
+	name := encodeNetBIOSName("*")
+	buf = append(buf, byte(len(name)))
+	buf = append(buf, name...)
+	buf = append(buf, 0x00) // root label terminator
+
+	buf = binary.BigEndian.AppendUint16(buf, 0x0021) // QTYPE: NBSTAT
+	buf = binary.BigEndian.AppendUint16(buf, 0x0001) // QCLASS: IN
+
+	return buf
+}
+
+// encodeNetBIOSName applies NetBIOS first-level encoding: pad to 16 bytes
+// and split every byte into two nibbles mapped onto 'A'-'P'.
+func encodeNetBIOSName(name string) []byte {
+	padded := make([]byte, 16)
+	copy(padded, name)
+
+	encoded := make([]byte, 0, 32)
+	for _, b := range padded {
+		encoded = append(encoded, 'A'+(b>>4), 'A'+(b&0x0f))
+	}
+	return encoded
+}
+
+// parseNBSTATResponse extracts the NetBIOS name table and unit (MAC)
+// address from an RFC 1002 NODE STATUS RESPONSE.
+func parseNBSTATResponse(msg []byte) (NetBIOSInfo, error) {
+	// Header (12 bytes) + encoded question name + QTYPE/QCLASS, then the
+	// answer's RR name/type/class/TTL/RDLENGTH before the payload.
+	off := 12
+	if off >= len(msg) {
+		return NetBIOSInfo{}, fmt.Errorf("netbios: response too short")
+	}
+
+	// Skip the name in the answer section. Since it just repeats the
+	// question name, real responders (Windows, Samba nmbd) almost always
+	// encode it as a 2-byte DNS compression pointer (top two bits set)
+	// rather than spelling it out again as a literal label.
+	if msg[off]&0xC0 == 0xC0 {
+		off += 2
+	} else {
+		nameLen := int(msg[off])
+		off += 1 + nameLen + 1 // length byte + name + root terminator
+	}
+
+	// TYPE(2) CLASS(2) TTL(4) RDLENGTH(2)
+	off += 10
+	if off >= len(msg) {
+		return NetBIOSInfo{}, fmt.Errorf("netbios: truncated response")
+	}
+
+	numNames := int(msg[off])
+	off++
+
+	var info NetBIOSInfo
+	for i := 0; i < numNames && off+18 <= len(msg); i++ {
+		nameField := msg[off : off+15]
+		name := strings.TrimRight(string(nameField), " ")
+		if name != "" {
+			info.Names = append(info.Names, name)
+		}
+		off += 18 // 15-byte name + 1-byte suffix + 2-byte flags
+	}
+
+	if off+6 <= len(msg) {
+		mac := msg[off : off+6]
+		info.MAC = fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", mac[0], mac[1], mac[2], mac[3], mac[4], mac[5])
+	}
+
+	return info, nil
+}
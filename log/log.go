@@ -0,0 +1,180 @@
+// Package log provides PDive2's leveled, per-subsystem logger. It replaces
+// ad-hoc colored Printf calls with Debugf/Infof/Warnf/Errorf, tagged by
+// subsystem (e.g. "disc", "port", "masscan", "svc", "brute").
+//
+// By default the global level is Info, and interactive runs render a
+// single colored line per event, in the same style as the tool's earlier
+// ad-hoc output. --log-format json switches to newline-delimited JSON
+// events ({ts, level, subsystem, msg}) for CI/SIEM pipelines. There is
+// deliberately no structured "fields" map yet: every call site today is a
+// plain Printf-style message, so a fields param would have no caller to
+// populate it. Add one (e.g. a trailing `fields map[string]any`, or a
+// variadic key/value list) once something actually needs per-event
+// structured data such as SIEM correlation by host/port.
+//
+// A subsystem can be forced to debug level regardless of the configured
+// --log-level via the PDIVE_TRACE environment variable, the way
+// syncthing's STTRACE works, e.g.:
+//
+//	PDIVE_TRACE=disc,masscan pdive2 -t 10.0.0.0/24
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Level is a logger verbosity level, ordered from most to least verbose.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// ParseLevel parses a --log-level flag value into a Level.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level: %s", s)
+	}
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+var (
+	mu     sync.Mutex
+	level  = LevelInfo
+	format = "text"
+	traced = parseTrace(os.Getenv("PDIVE_TRACE"))
+
+	debugColor = color.New(color.FgGreen)
+	infoColor  = color.New(color.FgCyan)
+	warnColor  = color.New(color.FgYellow)
+	errorColor = color.New(color.FgRed)
+)
+
+// parseTrace parses a PDIVE_TRACE-style comma-separated subsystem list.
+func parseTrace(s string) map[string]bool {
+	traced := make(map[string]bool)
+	for _, tok := range strings.Split(s, ",") {
+		tok = strings.ToLower(strings.TrimSpace(tok))
+		if tok != "" {
+			traced[tok] = true
+		}
+	}
+	return traced
+}
+
+// SetLevel sets the global minimum level for subsystems not named in
+// PDIVE_TRACE.
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// SetFormat sets the output format: "text" (colored, interactive) or
+// "json" (newline-delimited, for CI/SIEM pipelines).
+func SetFormat(f string) {
+	mu.Lock()
+	defer mu.Unlock()
+	format = f
+}
+
+// Debugf logs a debug-level message for subsystem. Debug messages are
+// only emitted when --log-level=debug or subsystem is listed in
+// PDIVE_TRACE.
+func Debugf(subsystem, f string, args ...interface{}) {
+	emit(LevelDebug, subsystem, fmt.Sprintf(f, args...))
+}
+
+// Infof logs an info-level message for subsystem.
+func Infof(subsystem, f string, args ...interface{}) {
+	emit(LevelInfo, subsystem, fmt.Sprintf(f, args...))
+}
+
+// Warnf logs a warn-level message for subsystem.
+func Warnf(subsystem, f string, args ...interface{}) {
+	emit(LevelWarn, subsystem, fmt.Sprintf(f, args...))
+}
+
+// Errorf logs an error-level message for subsystem.
+func Errorf(subsystem, f string, args ...interface{}) {
+	emit(LevelError, subsystem, fmt.Sprintf(f, args...))
+}
+
+func emit(l Level, subsystem, msg string) {
+	mu.Lock()
+	minLevel, outFormat := level, format
+	mu.Unlock()
+
+	if l < minLevel && !traced[subsystem] {
+		return
+	}
+
+	if outFormat == "json" {
+		emitJSON(l, subsystem, msg)
+		return
+	}
+	emitText(l, subsystem, msg)
+}
+
+// event is the newline-delimited JSON shape emitted by --log-format json.
+type event struct {
+	Time      string `json:"ts"`
+	Level     string `json:"level"`
+	Subsystem string `json:"subsystem"`
+	Message   string `json:"msg"`
+}
+
+func emitJSON(l Level, subsystem, msg string) {
+	json.NewEncoder(os.Stdout).Encode(event{
+		Time:      time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     l.String(),
+		Subsystem: subsystem,
+		Message:   msg,
+	})
+}
+
+func emitText(l Level, subsystem, msg string) {
+	switch l {
+	case LevelDebug:
+		debugColor.Printf("[+] %s\n", msg)
+	case LevelWarn:
+		warnColor.Printf("[!] %s\n", msg)
+	case LevelError:
+		errorColor.Printf("[-] %s\n", msg)
+	default:
+		infoColor.Printf("[*] %s\n", msg)
+	}
+}
@@ -1,1129 +1,2247 @@
-package main
-
-import (
-	"bufio"
-	"context"
-	"encoding/csv"
-	"fmt"
-	"net"
-	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"sort"
-	"strconv"
-	"strings"
-	"sync"
-	"time"
-
-	"github.com/fatih/color"
-	"github.com/spf13/cobra"
-)
-
-// Version information
-const (
-	Version = "2.0"
-	Banner  = `
-██████╗ ██████╗ ██╗██╗   ██╗███████╗██████╗
-██╔══██╗██╔══██╗██║██║   ██║██╔════╝╚════██╗
-██████╔╝██║  ██║██║██║   ██║█████╗   █████╔╝
-██╔═══╝ ██║  ██║██║╚██╗ ██╔╝██╔══╝  ██╔═══╝
-██║     ██████╔╝██║ ╚████╔╝ ███████╗███████╗
-╚═╝     ╚═════╝ ╚═╝  ╚═══╝  ╚══════╝╚══════╝
-`
-)
-
-// PortInfo represents information about a scanned port
-type PortInfo struct {
-	Port    int    `json:"port"`
-	State   string `json:"state"`
-	Service string `json:"service"`
-}
-
-// HostInfo represents information about a discovered host
-type HostInfo struct {
-	Host   string     `json:"host"`
-	Status string     `json:"status"`
-	Ports  []PortInfo `json:"ports"`
-}
-
-// ScanInfo represents metadata about the scan
-type ScanInfo struct {
-	Targets       []string  `json:"targets"`
-	StartTime     time.Time `json:"start_time"`
-	Scanner       string    `json:"scanner"`
-	DiscoveryMode string    `json:"discovery_mode"`
-}
-
-// ScanResults represents the complete scan results
-type ScanResults struct {
-	ScanInfo           ScanInfo   `json:"scan_info"`
-	Hosts              []HostInfo `json:"hosts"`
-	UnresponsiveHosts  int        `json:"unresponsive_hosts"`
-	mutex              sync.RWMutex
-}
-
-// PDive2 represents the main scanner configuration
-type PDive2 struct {
-	Targets       []string
-	OutputDir     string
-	Threads       int
-	DiscoveryMode string
-	Results       *ScanResults
-	EnableNmap    bool
-}
-
-// NewPDive2 creates a new PDive2 instance
-func NewPDive2(targets []string, outputDir string, threads int, discoveryMode string) *PDive2 {
-	return &PDive2{
-		Targets:       targets,
-		OutputDir:     outputDir,
-		Threads:       threads,
-		DiscoveryMode: discoveryMode,
-		Results: &ScanResults{
-			ScanInfo: ScanInfo{
-				Targets:       targets,
-				StartTime:     time.Now(),
-				Scanner:       fmt.Sprintf("PDive2 v%s", Version),
-				DiscoveryMode: discoveryMode,
-			},
-			Hosts: make([]HostInfo, 0),
-		},
-	}
-}
-
-// Colors for output
-var (
-	cyan   = color.New(color.FgCyan)
-	yellow = color.New(color.FgYellow)
-	green  = color.New(color.FgGreen)
-	red    = color.New(color.FgRed)
-)
-
-// PrintBanner prints the application banner
-func (p *PDive2) PrintBanner() {
-	targetsDisplay := strings.Join(p.Targets[:min(3, len(p.Targets))], ", ")
-	if len(p.Targets) > 3 {
-		targetsDisplay += fmt.Sprintf(" ... (+%d more)", len(p.Targets)-3)
-	}
-
-	cyan.Print(Banner)
-	yellow.Println("Dive deep into the network")
-	red.Println("For authorized security testing only!")
-	fmt.Println()
-
-	fmt.Printf("Targets (%d): %s\n", len(p.Targets), green.Sprintf(targetsDisplay))
-	fmt.Printf("Output Directory: %s\n", green.Sprint(p.OutputDir))
-	fmt.Printf("Threads: %s\n", green.Sprint(p.Threads))
-	fmt.Printf("Discovery Mode: %s\n", green.Sprint(strings.ToUpper(p.DiscoveryMode)))
-	fmt.Println()
-}
-
-// ValidateTargets validates if all targets are valid IP addresses, network ranges, or hostnames
-func (p *PDive2) ValidateTargets() bool {
-	var validTargets []string
-	var invalidTargets []string
-
-	for _, target := range p.Targets {
-		if isValidTarget(target) {
-			validTargets = append(validTargets, target)
-		} else {
-			invalidTargets = append(invalidTargets, target)
-		}
-	}
-
-	if len(invalidTargets) > 0 {
-		red.Printf("[-] Invalid targets: %s\n", strings.Join(invalidTargets, ", "))
-	}
-
-	p.Targets = validTargets
-	return len(validTargets) > 0
-}
-
-// isValidTarget checks if a target is a valid IP, CIDR, or hostname
-func isValidTarget(target string) bool {
-	// Try parsing as IP/CIDR
-	if _, _, err := net.ParseCIDR(target); err == nil {
-		return true
-	}
-	if net.ParseIP(target) != nil {
-		return true
-	}
-
-	// Try resolving as hostname
-	if _, err := net.LookupHost(target); err == nil {
-		return true
-	}
-
-	return false
-}
-
-// expandTargets expands CIDR ranges to individual IPs
-func (p *PDive2) expandTargets() []string {
-	var allHosts []string
-
-	for _, target := range p.Targets {
-		if strings.Contains(target, "/") {
-			// CIDR range
-			if ip, ipnet, err := net.ParseCIDR(target); err == nil {
-				for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); inc(ip) {
-					allHosts = append(allHosts, ip.String())
-				}
-			}
-		} else {
-			allHosts = append(allHosts, target)
-		}
-	}
-
-	return removeDuplicates(allHosts)
-}
-
-// inc increments an IP address
-func inc(ip net.IP) {
-	for j := len(ip) - 1; j >= 0; j-- {
-		ip[j]++
-		if ip[j] > 0 {
-			break
-		}
-	}
-}
-
-// removeDuplicates removes duplicate strings from a slice
-func removeDuplicates(slice []string) []string {
-	keys := make(map[string]bool)
-	var result []string
-
-	for _, item := range slice {
-		if !keys[item] {
-			keys[item] = true
-			result = append(result, item)
-		}
-	}
-
-	return result
-}
-
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-// HostDiscovery performs host discovery using ping and port-based detection
-func (p *PDive2) HostDiscovery() []string {
-	yellow.Println("\n[+] Starting Host Discovery...")
-
-	allHosts := p.expandTargets()
-	liveHosts := make(map[string]bool)
-	var mu sync.Mutex
-
-	// Common ports for host discovery fallback
-	discoveryPorts := []int{80, 443, 22, 21, 25, 53, 135, 139, 445}
-
-	// Phase 1: Ping discovery
-	cyan.Println("[*] Phase 1: Ping discovery...")
-	var wg sync.WaitGroup
-	hostChan := make(chan string, len(allHosts))
-
-	for _, host := range allHosts {
-		hostChan <- host
-	}
-	close(hostChan)
-
-	// Start ping workers
-	for i := 0; i < p.Threads; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for host := range hostChan {
-				if p.pingHost(host) {
-					mu.Lock()
-					liveHosts[host] = true
-					mu.Unlock()
-					green.Printf("[+] Host discovered (ping): %s\n", host)
-				}
-			}
-		}()
-	}
-
-	wg.Wait()
-
-	// Phase 2: Port-based discovery for non-ping responsive hosts
-	var nonPingHosts []string
-	for _, host := range allHosts {
-		if !liveHosts[host] {
-			nonPingHosts = append(nonPingHosts, host)
-		}
-	}
-
-	if len(nonPingHosts) > 0 {
-		cyan.Printf("[*] Phase 2: Port-based discovery for %d non-ping responsive hosts...\n", len(nonPingHosts))
-
-		hostChan = make(chan string, len(nonPingHosts))
-		for _, host := range nonPingHosts {
-			hostChan <- host
-		}
-		close(hostChan)
-
-		// Start port discovery workers
-		for i := 0; i < min(p.Threads, 20); i++ {
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				for host := range hostChan {
-					if p.portDiscovery(host, discoveryPorts) {
-						mu.Lock()
-						liveHosts[host] = true
-						mu.Unlock()
-						green.Printf("[+] Host discovered (port): %s\n", host)
-					}
-				}
-			}()
-		}
-
-		wg.Wait()
-	}
-
-	// Convert map to slice
-	var liveHostsList []string
-	for host := range liveHosts {
-		liveHostsList = append(liveHostsList, host)
-	}
-
-	// Update results
-	p.Results.mutex.Lock()
-	for _, host := range liveHostsList {
-		p.Results.Hosts = append(p.Results.Hosts, HostInfo{
-			Host:   host,
-			Status: "up",
-			Ports:  make([]PortInfo, 0),
-		})
-	}
-	p.Results.UnresponsiveHosts = len(allHosts) - len(liveHostsList)
-	p.Results.mutex.Unlock()
-
-	cyan.Printf("\n[*] Host discovery completed. Found %d live hosts from %d total hosts.\n",
-		len(liveHostsList), len(allHosts))
-	cyan.Printf("[*] Ping responsive: %d, Port responsive: %d\n",
-		len(liveHosts)-len(nonPingHosts), len(liveHostsList)-(len(liveHosts)-len(nonPingHosts)))
-
-	return liveHostsList
-}
-
-// pingHost performs a ping test on a host
-func (p *PDive2) pingHost(host string) bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "ping", "-c", "1", "-W", "2", host)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-
-	return cmd.Run() == nil
-}
-
-// portDiscovery tries to connect to common ports to detect live hosts
-func (p *PDive2) portDiscovery(host string, ports []int) bool {
-	for _, port := range ports {
-		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), 3*time.Second)
-		if err == nil {
-			conn.Close()
-			return true
-		}
-	}
-	return false
-}
-
-// PortScan performs port scanning on discovered hosts
-func (p *PDive2) PortScan(hosts []string) {
-	yellow.Println("\n[+] Starting Port Scanning...")
-
-	commonPorts := []int{21, 22, 23, 25, 53, 80, 110, 111, 135, 139, 143, 443, 993, 995, 1723, 3306, 3389, 5432, 5900, 8080, 8443}
-
-	var wg sync.WaitGroup
-	hostChan := make(chan string, len(hosts))
-
-	for _, host := range hosts {
-		hostChan <- host
-	}
-	close(hostChan)
-
-	// Start port scanning workers
-	for i := 0; i < p.Threads; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for host := range hostChan {
-				p.scanHostPorts(host, commonPorts)
-			}
-		}()
-	}
-
-	wg.Wait()
-}
-
-// scanHostPorts scans ports for a specific host
-func (p *PDive2) scanHostPorts(host string, ports []int) {
-	cyan.Printf("\n[*] Scanning %s...\n", host)
-	var openPorts []PortInfo
-
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	portChan := make(chan int, len(ports))
-
-	for _, port := range ports {
-		portChan <- port
-	}
-	close(portChan)
-
-	// Start port workers
-	for i := 0; i < min(p.Threads, 50); i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for port := range portChan {
-				if p.scanPort(host, port) {
-					mu.Lock()
-					openPorts = append(openPorts, PortInfo{
-						Port:    port,
-						State:   "open",
-						Service: "",
-					})
-					mu.Unlock()
-					green.Printf("[+] Open port found: %s:%d\n", host, port)
-				}
-			}
-		}()
-	}
-
-	wg.Wait()
-
-	// Update results
-	p.Results.mutex.Lock()
-	for i := range p.Results.Hosts {
-		if p.Results.Hosts[i].Host == host {
-			p.Results.Hosts[i].Ports = openPorts
-			break
-		}
-	}
-	p.Results.mutex.Unlock()
-}
-
-// scanPort scans a specific port on a host
-func (p *PDive2) scanPort(host string, port int) bool {
-	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), 3*time.Second)
-	if err != nil {
-		return false
-	}
-	conn.Close()
-	return true
-}
-
-// ServiceEnumeration performs service enumeration on open ports
-func (p *PDive2) ServiceEnumeration(hosts []string) {
-	yellow.Println("\n[+] Starting Service Enumeration...")
-
-	serviceMap := map[int]string{
-		21: "ftp", 22: "ssh", 23: "telnet", 25: "smtp", 53: "dns",
-		80: "http", 110: "pop3", 135: "rpc", 139: "netbios", 143: "imap",
-		443: "https", 993: "imaps", 995: "pop3s", 1723: "pptp",
-		3306: "mysql", 3389: "rdp", 5432: "postgresql", 5900: "vnc",
-		8080: "http-alt", 8443: "https-alt",
-	}
-
-	for _, host := range hosts {
-		p.Results.mutex.Lock()
-		var hostIndex int = -1
-		for i, h := range p.Results.Hosts {
-			if h.Host == host {
-				hostIndex = i
-				break
-			}
-		}
-
-		if hostIndex != -1 {
-			for j, port := range p.Results.Hosts[hostIndex].Ports {
-				service := p.enumerateService(host, port.Port, serviceMap)
-				p.Results.Hosts[hostIndex].Ports[j].Service = service
-				green.Printf("[+] Service identified: %s:%d -> %s\n", host, port.Port, service)
-			}
-		}
-		p.Results.mutex.Unlock()
-	}
-}
-
-// enumerateService performs basic service enumeration
-func (p *PDive2) enumerateService(host string, port int, serviceMap map[int]string) string {
-	service, exists := serviceMap[port]
-	if !exists {
-		return "unknown"
-	}
-
-	// Enhanced HTTP service detection
-	if service == "http" || service == "https" || service == "http-alt" || service == "https-alt" {
-		protocol := "http"
-		if service == "https" || service == "https-alt" {
-			protocol = "https"
-		}
-
-		portStr := ""
-		if port != 80 && port != 443 {
-			portStr = fmt.Sprintf(":%d", port)
-		}
-
-		url := fmt.Sprintf("%s://%s%s", protocol, host, portStr)
-
-		client := &http.Client{
-			Timeout: 5 * time.Second,
-		}
-
-		if resp, err := client.Get(url); err == nil {
-			defer resp.Body.Close()
-			if server := resp.Header.Get("Server"); server != "" {
-				return fmt.Sprintf("%s (%s)", service, server)
-			}
-		}
-	}
-
-	return service
-}
-
-// PassiveDiscovery performs passive discovery using amass only
-func (p *PDive2) PassiveDiscovery() []string {
-	yellow.Println("\n[+] Starting Passive Discovery (amass only)...")
-
-	var discoveredHosts []string
-
-	for _, target := range p.Targets {
-		domain := p.extractDomain(target)
-		if domain == "" {
-			continue
-		}
-
-		cyan.Printf("[*] Performing passive discovery on domain: %s\n", domain)
-		hosts := p.amassDiscovery(domain)
-		discoveredHosts = append(discoveredHosts, hosts...)
-	}
-
-	discoveredHosts = removeDuplicates(discoveredHosts)
-
-	// Add discovered hosts to results
-	p.Results.mutex.Lock()
-	for _, host := range discoveredHosts {
-		p.Results.Hosts = append(p.Results.Hosts, HostInfo{
-			Host:   host,
-			Status: "discovered",
-			Ports:  make([]PortInfo, 0),
-		})
-	}
-	p.Results.mutex.Unlock()
-
-	cyan.Printf("\n[*] Passive discovery completed. Found %d hosts.\n", len(discoveredHosts))
-
-	return discoveredHosts
-}
-
-// extractDomain extracts domain name from target
-func (p *PDive2) extractDomain(target string) string {
-	// If it's an IP or CIDR, skip
-	if net.ParseIP(target) != nil {
-		return ""
-	}
-	if _, _, err := net.ParseCIDR(target); err == nil {
-		return ""
-	}
-
-	return strings.ToLower(strings.TrimSpace(target))
-}
-
-// amassDiscovery uses amass for passive subdomain enumeration
-func (p *PDive2) amassDiscovery(domain string) []string {
-	var discoveredHosts []string
-
-	cyan.Printf("[*] Running amass on %s...\n", domain)
-
-	// Check if amass is available
-	if _, err := exec.LookPath("amass"); err != nil {
-		red.Println("[-] Amass not found in PATH, skipping amass discovery")
-		yellow.Println("[*] Install amass from: https://github.com/OWASP/Amass")
-		return discoveredHosts
-	}
-
-	// Run amass with specified options (passive mode only)
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "amass", "enum", "-d", domain, "-passive")
-	output, err := cmd.Output()
-
-	if err != nil {
-		red.Printf("[-] Amass failed: %v\n", err)
-		return discoveredHosts
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" {
-			discoveredHosts = append(discoveredHosts, line)
-			green.Printf("[+] Amass discovered: %s\n", line)
-		}
-	}
-
-	if len(discoveredHosts) == 0 {
-		yellow.Printf("[*] Amass completed but found no subdomains for %s\n", domain)
-	}
-
-	return discoveredHosts
-}
-
-// MasscanScan performs fast port scanning using masscan
-func (p *PDive2) MasscanScan(hosts []string) map[string][]PortInfo {
-	yellow.Println("\n[+] Starting Fast Port Scan (masscan)...")
-
-	if len(hosts) == 0 {
-		red.Println("[-] No hosts provided for masscan")
-		return make(map[string][]PortInfo)
-	}
-
-	// Check if masscan is available
-	if _, err := exec.LookPath("masscan"); err != nil {
-		red.Println("[-] Masscan not found in PATH, falling back to basic port scan")
-		yellow.Println("[*] Install masscan from: https://github.com/robertdavidgraham/masscan")
-		p.PortScan(hosts)
-
-		// Convert results format
-		results := make(map[string][]PortInfo)
-		p.Results.mutex.RLock()
-		for _, host := range p.Results.Hosts {
-			if len(host.Ports) > 0 {
-				results[host.Host] = host.Ports
-			}
-		}
-		p.Results.mutex.RUnlock()
-		return results
-	}
-
-	masscanResults := make(map[string][]PortInfo)
-
-	// Create temporary target file for masscan
-	tmpfile, err := os.CreateTemp("", "masscan_targets_*.txt")
-	if err != nil {
-		red.Printf("[-] Failed to create temp file: %v\n", err)
-		return masscanResults
-	}
-	defer os.Remove(tmpfile.Name())
-
-	for _, host := range hosts {
-		fmt.Fprintln(tmpfile, host)
-	}
-	tmpfile.Close()
-
-	cyan.Printf("[*] Running masscan on %d hosts...\n", len(hosts))
-
-	// Run masscan with output in list format
-	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "masscan", "-iL", tmpfile.Name(), "-p1-65535", "--rate", "1000", "--output-format", "list")
-	output, err := cmd.Output()
-
-	if err != nil {
-		red.Printf("[-] Masscan failed: %v\n", err)
-		yellow.Println("[*] Falling back to basic port scan...")
-		p.PortScan(hosts)
-
-		// Convert results format
-		results := make(map[string][]PortInfo)
-		p.Results.mutex.RLock()
-		for _, host := range p.Results.Hosts {
-			if len(host.Ports) > 0 {
-				results[host.Host] = host.Ports
-			}
-		}
-		p.Results.mutex.RUnlock()
-		return results
-	}
-
-	// Parse masscan output
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			// Masscan list format: "open tcp 80 1.2.3.4 1234567890"
-			parts := strings.Fields(line)
-			if len(parts) >= 4 && parts[0] == "open" && parts[1] == "tcp" {
-				portStr := parts[2]
-				host := parts[3]
-
-				if port, err := strconv.Atoi(portStr); err == nil {
-					if _, exists := masscanResults[host]; !exists {
-						masscanResults[host] = make([]PortInfo, 0)
-					}
-					masscanResults[host] = append(masscanResults[host], PortInfo{
-						Port:    port,
-						State:   "open",
-						Service: "",
-					})
-
-					green.Printf("[+] Masscan found: %s:%s\n", host, portStr)
-				}
-			}
-		}
-	}
-
-	cyan.Printf("\n[*] Masscan completed. Found ports on %d hosts.\n", len(masscanResults))
-
-	// Update results with masscan findings
-	p.Results.mutex.Lock()
-	for _, host := range hosts {
-		var hostIndex int = -1
-		for i, h := range p.Results.Hosts {
-			if h.Host == host {
-				hostIndex = i
-				break
-			}
-		}
-
-		if hostIndex == -1 {
-			p.Results.Hosts = append(p.Results.Hosts, HostInfo{
-				Host:   host,
-				Status: "up",
-				Ports:  make([]PortInfo, 0),
-			})
-			hostIndex = len(p.Results.Hosts) - 1
-		}
-
-		if ports, exists := masscanResults[host]; exists {
-			p.Results.Hosts[hostIndex].Ports = append(p.Results.Hosts[hostIndex].Ports, ports...)
-		}
-	}
-	p.Results.mutex.Unlock()
-
-	return masscanResults
-}
-
-// GenerateReport generates comprehensive scan reports in text and CSV format
-func (p *PDive2) GenerateReport() {
-	yellow.Println("\n[+] Generating Reports...")
-
-	// Create output directory
-	if err := os.MkdirAll(p.OutputDir, 0755); err != nil {
-		red.Printf("[-] Failed to create output directory: %v\n", err)
-		return
-	}
-
-	timestamp := time.Now().Format("20060102_150405")
-	endTime := time.Now()
-
-	p.Results.mutex.RLock()
-	totalHosts := len(p.Results.Hosts)
-	totalPorts := 0
-	for _, host := range p.Results.Hosts {
-		totalPorts += len(host.Ports)
-	}
-	p.Results.mutex.RUnlock()
-
-	// Generate detailed text report
-	txtFile := filepath.Join(p.OutputDir, fmt.Sprintf("recon_report_%s.txt", timestamp))
-	if f, err := os.Create(txtFile); err == nil {
-		defer f.Close()
-
-		fmt.Fprintln(f, "PDIVE2 DETAILED SCAN REPORT")
-		fmt.Fprintln(f, strings.Repeat("=", 60))
-		fmt.Fprintln(f)
-
-		// Summary section
-		fmt.Fprintln(f, "SCAN SUMMARY")
-		fmt.Fprintln(f, strings.Repeat("-", 20))
-		fmt.Fprintln(f, "Targets:")
-		for _, target := range p.Targets {
-			fmt.Fprintf(f, "  %s\n", target)
-		}
-		fmt.Fprintf(f, "\nScan Start Time: %s\n", p.Results.ScanInfo.StartTime.Format("2006-01-02 15:04:05"))
-		fmt.Fprintf(f, "Scan End Time: %s\n", endTime.Format("2006-01-02 15:04:05"))
-		fmt.Fprintf(f, "Scanner Version: %s\n", p.Results.ScanInfo.Scanner)
-		fmt.Fprintf(f, "Total Live Hosts: %d\n", totalHosts)
-		fmt.Fprintf(f, "Total Open Ports: %d\n", totalPorts)
-		fmt.Fprintf(f, "Unresponsive Hosts: %d\n\n", p.Results.UnresponsiveHosts)
-
-		// Detailed results section
-		fmt.Fprintln(f, "DETAILED RESULTS")
-		fmt.Fprintln(f, strings.Repeat("-", 20))
-
-		p.Results.mutex.RLock()
-		if len(p.Results.Hosts) > 0 {
-			for _, host := range p.Results.Hosts {
-				fmt.Fprintf(f, "\nHost: %s\n", host.Host)
-				fmt.Fprintln(f, strings.Repeat("=", len(host.Host)+6))
-				if len(host.Ports) > 0 {
-					fmt.Fprintln(f, "Open Ports:")
-					for _, port := range host.Ports {
-						service := port.Service
-						if service == "" {
-							service = "unknown"
-						}
-						fmt.Fprintf(f, "  %5d/tcp  %s\n", port.Port, service)
-					}
-				} else {
-					fmt.Fprintln(f, "  No open ports detected")
-				}
-			}
-		} else {
-			fmt.Fprintln(f, "No live hosts discovered")
-		}
-		p.Results.mutex.RUnlock()
-	}
-
-	// Generate CSV report
-	csvFile := filepath.Join(p.OutputDir, fmt.Sprintf("recon_results_%s.csv", timestamp))
-	if f, err := os.Create(csvFile); err == nil {
-		defer f.Close()
-
-		writer := csv.NewWriter(f)
-		defer writer.Flush()
-
-		// CSV Headers
-		writer.Write([]string{"Host", "Port", "Protocol", "State", "Service", "Scan_Time"})
-
-		// CSV Data
-		scanTime := p.Results.ScanInfo.StartTime.Format("2006-01-02 15:04:05")
-
-		p.Results.mutex.RLock()
-		if len(p.Results.Hosts) > 0 {
-			for _, host := range p.Results.Hosts {
-				if len(host.Ports) > 0 {
-					for _, port := range host.Ports {
-						service := port.Service
-						if service == "" {
-							service = "unknown"
-						}
-						writer.Write([]string{
-							host.Host,
-							strconv.Itoa(port.Port),
-							"tcp",
-							port.State,
-							service,
-							scanTime,
-						})
-					}
-				} else {
-					// Host is up but no ports detected
-					writer.Write([]string{host.Host, "", "", "host_up", "no_open_ports", scanTime})
-				}
-			}
-		}
-		p.Results.mutex.RUnlock()
-	}
-
-	green.Println("[+] Reports saved to:")
-	fmt.Printf("  - Detailed Report: %s\n", txtFile)
-	fmt.Printf("  - CSV Data: %s\n", csvFile)
-}
-
-// GeneratePassiveReport generates simple report for passive discovery mode
-func (p *PDive2) GeneratePassiveReport() {
-	yellow.Println("\n[+] Generating Passive Discovery Report...")
-
-	// Create output directory
-	if err := os.MkdirAll(p.OutputDir, 0755); err != nil {
-		red.Printf("[-] Failed to create output directory: %v\n", err)
-		return
-	}
-
-	timestamp := time.Now().Format("20060102_150405")
-	endTime := time.Now()
-
-	p.Results.mutex.RLock()
-	totalHosts := len(p.Results.Hosts)
-	p.Results.mutex.RUnlock()
-
-	// Generate simple text report for passive mode
-	txtFile := filepath.Join(p.OutputDir, fmt.Sprintf("passive_discovery_%s.txt", timestamp))
-	if f, err := os.Create(txtFile); err == nil {
-		defer f.Close()
-
-		fmt.Fprintln(f, "PDIVE2 PASSIVE DISCOVERY REPORT")
-		fmt.Fprintln(f, strings.Repeat("=", 60))
-		fmt.Fprintln(f)
-
-		// Summary section
-		fmt.Fprintln(f, "DISCOVERY SUMMARY")
-		fmt.Fprintln(f, strings.Repeat("-", 20))
-		fmt.Fprintln(f, "Targets:")
-		for _, target := range p.Targets {
-			fmt.Fprintf(f, "  %s\n", target)
-		}
-		fmt.Fprintf(f, "\nScan Start Time: %s\n", p.Results.ScanInfo.StartTime.Format("2006-01-02 15:04:05"))
-		fmt.Fprintf(f, "Scan End Time: %s\n", endTime.Format("2006-01-02 15:04:05"))
-		fmt.Fprintf(f, "Scanner Version: %s\n", p.Results.ScanInfo.Scanner)
-		fmt.Fprintf(f, "Discovery Mode: %s\n", strings.ToUpper(p.Results.ScanInfo.DiscoveryMode))
-		fmt.Fprintf(f, "Total Discovered Hosts: %d\n\n", totalHosts)
-
-		// Host list section
-		fmt.Fprintln(f, "DISCOVERED HOSTS")
-		fmt.Fprintln(f, strings.Repeat("-", 20))
-
-		p.Results.mutex.RLock()
-		if len(p.Results.Hosts) > 0 {
-			var hosts []string
-			for _, host := range p.Results.Hosts {
-				hosts = append(hosts, host.Host)
-			}
-			sort.Strings(hosts)
-			for _, host := range hosts {
-				fmt.Fprintln(f, host)
-			}
-		} else {
-			fmt.Fprintln(f, "No hosts discovered")
-		}
-		p.Results.mutex.RUnlock()
-	}
-
-	// Generate simple CSV with just hostnames
-	csvFile := filepath.Join(p.OutputDir, fmt.Sprintf("passive_hosts_%s.csv", timestamp))
-	if f, err := os.Create(csvFile); err == nil {
-		defer f.Close()
-
-		writer := csv.NewWriter(f)
-		defer writer.Flush()
-
-		// CSV Headers
-		writer.Write([]string{"Host", "Discovery_Method", "Scan_Time"})
-
-		// CSV Data
-		scanTime := p.Results.ScanInfo.StartTime.Format("2006-01-02 15:04:05")
-
-		p.Results.mutex.RLock()
-		if len(p.Results.Hosts) > 0 {
-			for _, host := range p.Results.Hosts {
-				writer.Write([]string{host.Host, "passive", scanTime})
-			}
-		}
-		p.Results.mutex.RUnlock()
-	}
-
-	green.Println("[+] Passive discovery reports saved to:")
-	fmt.Printf("  - Host List Report: %s\n", txtFile)
-	fmt.Printf("  - CSV Host List: %s\n", csvFile)
-}
-
-// RunScan executes complete reconnaissance scan
-func (p *PDive2) RunScan() {
-	if !p.ValidateTargets() {
-		red.Println("[-] No valid targets found")
-		return
-	}
-
-	p.PrintBanner()
-
-	if p.DiscoveryMode == "passive" {
-		// Passive discovery mode - use passive techniques only
-		discoveredHosts := p.PassiveDiscovery()
-		if len(discoveredHosts) == 0 {
-			red.Println("[-] No hosts discovered through passive methods.")
-			return
-		}
-
-		// In passive mode, only return the list of discovered hosts
-		yellow.Println("\n[+] PASSIVE DISCOVERY RESULTS")
-		yellow.Println(strings.Repeat("=", 50))
-		cyan.Printf("Total hosts discovered: %d\n\n", len(discoveredHosts))
-
-		green.Println("Discovered hosts:")
-		sort.Strings(discoveredHosts)
-		for _, host := range discoveredHosts {
-			fmt.Println(host)
-		}
-
-		// Generate simple report for passive mode
-		p.GeneratePassiveReport()
-
-	} else {
-		// Active discovery mode - amass -> host discovery -> masscan -> nmap
-		yellow.Println("\n[+] Starting Active Discovery Mode")
-		cyan.Println("[*] Phase 1: Passive subdomain discovery with amass")
-
-		// First, run amass to discover subdomains
-		amassHosts := p.PassiveDiscovery()
-
-		// Then do traditional host discovery
-		cyan.Println("\n[*] Phase 2: Host discovery and connectivity check")
-		liveHosts := p.HostDiscovery()
-
-		// Combine amass results with live host discovery
-		allDiscoveredHosts := removeDuplicates(append(amassHosts, liveHosts...))
-
-		if len(allDiscoveredHosts) == 0 {
-			red.Println("[-] No live hosts discovered.")
-			return
-		}
-
-		// Ensure all discovered hosts are initialized in results before proceeding
-		p.Results.mutex.Lock()
-		hostMap := make(map[string]bool)
-		for _, host := range p.Results.Hosts {
-			hostMap[host.Host] = true
-		}
-		for _, host := range allDiscoveredHosts {
-			if !hostMap[host] {
-				p.Results.Hosts = append(p.Results.Hosts, HostInfo{
-					Host:   host,
-					Status: "up",
-					Ports:  make([]PortInfo, 0),
-				})
-			}
-		}
-		p.Results.mutex.Unlock()
-
-		cyan.Println("\n[*] Phase 3: Fast port scanning with masscan")
-		// Use masscan for fast port discovery
-		masscanResults := p.MasscanScan(allDiscoveredHosts)
-
-		if p.EnableNmap && len(masscanResults) > 0 {
-			cyan.Println("\n[*] Phase 4: Detailed service enumeration with nmap")
-			// Note: Nmap integration would be implemented here
-			yellow.Println("[*] Nmap integration not yet implemented in Go version")
-		}
-
-		if len(masscanResults) > 0 {
-			// Do basic service enumeration on masscan results
-			cyan.Println("\n[*] Phase 4: Basic service identification")
-			p.ServiceEnumeration(allDiscoveredHosts)
-		}
-
-		// Generate full report for active mode
-		p.GenerateReport()
-	}
-
-	green.Println("\n[+] Reconnaissance scan completed!")
-}
-
-// LoadTargetsFromFile loads targets from a text file, one per line
-func LoadTargetsFromFile(filePath string) ([]string, error) {
-	var targets []string
-
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("target file not found: %s", filePath)
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		target := strings.TrimSpace(scanner.Text())
-		if target != "" && !strings.HasPrefix(target, "#") {
-			targets = append(targets, target)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading target file: %v", err)
-	}
-
-	return targets, nil
-}
-
-// CLI command configuration
-var (
-	targetFlag     string
-	targetFileFlag string
-	outputFlag     string
-	threadsFlag    int
-	modeFlag       string
-	nmapFlag       bool
-)
-
-func main() {
-	var rootCmd = &cobra.Command{
-		Use:     "pdive2",
-		Short:   "PDIve2 - Automated Penetration Testing Discovery Tool (Go Edition)",
-		Long: `PDIve2 - Automated Penetration Testing Discovery Tool (Go Edition)
-Dive deep into the network - A defensive security tool for authorized network reconnaissance and vulnerability assessment.
-
-Examples:
-  pdive2 -t 192.168.1.0/24
-  pdive2 -t 10.0.0.1 --nmap
-  pdive2 -f targets.txt -o /tmp/scan_results -T 100
-  pdive2 -t "192.168.1.1,example.com,10.0.0.0/24"
-  pdive2 -t example.com -m passive
-  pdive2 -t testphp.vulnweb.com -m active --nmap`,
-		Version: Version,
-		Run: func(cmd *cobra.Command, args []string) {
-			// Validate mode and nmap combination
-			if modeFlag == "passive" && nmapFlag {
-				red.Println("[-] Error: --nmap flag is not compatible with passive mode")
-				os.Exit(1)
-			}
-
-			var targets []string
-			var err error
-
-			if targetFileFlag != "" {
-				targets, err = LoadTargetsFromFile(targetFileFlag)
-				if err != nil {
-					red.Printf("[-] %v\n", err)
-					os.Exit(1)
-				}
-				if len(targets) == 0 {
-					red.Println("[-] No valid targets found in file")
-					os.Exit(1)
-				}
-			} else if targetFlag != "" {
-				if strings.Contains(targetFlag, ",") {
-					for _, t := range strings.Split(targetFlag, ",") {
-						t = strings.TrimSpace(t)
-						if t != "" {
-							targets = append(targets, t)
-						}
-					}
-				} else {
-					targets = []string{targetFlag}
-				}
-			} else {
-				red.Println("[-] Either -t or -f flag is required")
-				os.Exit(1)
-			}
-
-			red.Println("WARNING: This tool is for authorized security testing only!")
-			red.Println("Ensure you have proper permission before scanning any network.\n")
-
-			targetsDisplay := strings.Join(targets[:min(3, len(targets))], ", ")
-			if len(targets) > 3 {
-				targetsDisplay += fmt.Sprintf(" ... (+%d more)", len(targets)-3)
-			}
-
-			fmt.Printf("Targets to scan: %s\n", targetsDisplay)
-			fmt.Print("Do you have authorization to scan these targets? (y/N): ")
-
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
-
-			if response != "y" {
-				fmt.Println("Scan aborted.")
-				os.Exit(1)
-			}
-
-			pdive := NewPDive2(targets, outputFlag, threadsFlag, modeFlag)
-			pdive.EnableNmap = nmapFlag
-			pdive.RunScan()
-		},
-	}
-
-	rootCmd.Flags().StringVarP(&targetFlag, "target", "t", "", "Target IP address, hostname, CIDR range, or comma-separated list")
-	rootCmd.Flags().StringVarP(&targetFileFlag, "file", "f", "", "File containing targets (one per line)")
-	rootCmd.Flags().StringVarP(&outputFlag, "output", "o", "recon_output", "Output directory (default: recon_output)")
-	rootCmd.Flags().IntVarP(&threadsFlag, "threads", "T", 50, "Number of threads (default: 50)")
-	rootCmd.Flags().StringVarP(&modeFlag, "mode", "m", "active", "Discovery mode: active (default) or passive")
-	rootCmd.Flags().BoolVar(&nmapFlag, "nmap", false, "Enable detailed Nmap scanning (Active mode only)")
-
-	rootCmd.MarkFlagsMutuallyExclusive("target", "file")
-
-	if err := rootCmd.Execute(); err != nil {
-		red.Printf("Error: %v\n", err)
-		os.Exit(1)
-	}
-}
\ No newline at end of file
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/hero2zero/pdive2/api"
+	"github.com/hero2zero/pdive2/bruteforce"
+	"github.com/hero2zero/pdive2/discovery"
+	"github.com/hero2zero/pdive2/log"
+	"github.com/hero2zero/pdive2/nmap"
+	"github.com/hero2zero/pdive2/passive"
+	"github.com/hero2zero/pdive2/report"
+	"github.com/hero2zero/pdive2/scan"
+	"github.com/hero2zero/pdive2/serviceprobe"
+	"github.com/hero2zero/pdive2/state"
+	"github.com/hero2zero/pdive2/vuln"
+)
+
+// Version information
+const (
+	Version = "2.0"
+	Banner  = `
+██████╗ ██████╗ ██╗██╗   ██╗███████╗██████╗
+██╔══██╗██╔══██╗██║██║   ██║██╔════╝╚════██╗
+██████╔╝██║  ██║██║██║   ██║█████╗   █████╔╝
+██╔═══╝ ██║  ██║██║╚██╗ ██╔╝██╔══╝  ██╔═══╝
+██║     ██████╔╝██║ ╚████╔╝ ███████╗███████╗
+╚═╝     ╚═════╝ ╚═╝  ╚═══╝  ╚══════╝╚══════╝
+`
+)
+
+// PortInfo represents information about a scanned port
+type PortInfo struct {
+	Port     int       `json:"port"`
+	State    string    `json:"state"`
+	Service  string    `json:"service"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Finding is a security-relevant observation made while fingerprinting
+// the service on a port (e.g. an outdated protocol dialect or a CVE
+// signature), surfaced by the serviceprobe package.
+type Finding struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Evidence string `json:"evidence"`
+	CVE      string `json:"cve,omitempty"`
+}
+
+// HostInfo represents information about a discovered host
+type HostInfo struct {
+	Host        string       `json:"host"`
+	Status      string       `json:"status"`
+	Ports       []PortInfo   `json:"ports"`
+	Hostname    string       `json:"hostname,omitempty"`
+	MAC         string       `json:"mac,omitempty"`
+	Services    []string     `json:"services,omitempty"`
+	Credentials []Credential `json:"credentials,omitempty"`
+	// OSMatches are nmap -O's OS guesses, best match first, populated
+	// when --nmap is set.
+	OSMatches []string `json:"os_matches,omitempty"`
+	// Sources names which PassiveDiscovery source(s) (ct, shodan,
+	// dns-brute, ...) reported this host, populated for passively
+	// discovered hosts only.
+	Sources []string `json:"sources,omitempty"`
+}
+
+// Credential is a valid username/password pair recovered by the
+// bruteforce package against one of this host's services.
+type Credential struct {
+	Service  string `json:"service"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ScanInfo represents metadata about the scan
+type ScanInfo struct {
+	Targets       []string  `json:"targets"`
+	StartTime     time.Time `json:"start_time"`
+	Scanner       string    `json:"scanner"`
+	DiscoveryMode string    `json:"discovery_mode"`
+	// ResumeToken identifies this scan across interruptions; it is loaded
+	// from the state store on --resume so reports stitch together.
+	ResumeToken string `json:"resume_token,omitempty"`
+}
+
+// Vulnerability is a single confirmed match from the vuln-scanning
+// phase: a vuln.Template matched against a host:port pair.
+type Vulnerability struct {
+	ID        string   `json:"id"`
+	Severity  string   `json:"severity"`
+	Host      string   `json:"host"`
+	Port      int      `json:"port"`
+	MatchedAt string   `json:"matched_at"`
+	Extracted []string `json:"extracted,omitempty"`
+}
+
+// ScanResults represents the complete scan results
+type ScanResults struct {
+	ScanInfo          ScanInfo        `json:"scan_info"`
+	Hosts             []HostInfo      `json:"hosts"`
+	UnresponsiveHosts int             `json:"unresponsive_hosts"`
+	Vulnerabilities   []Vulnerability `json:"vulnerabilities,omitempty"`
+	mutex             sync.RWMutex
+}
+
+// Snapshot converts the current results into a format-agnostic
+// report.Document that the report package can render.
+func (r *ScanResults) Snapshot(endTime time.Time) report.Document {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	hosts := make([]report.Host, 0, len(r.Hosts))
+	totalPorts := 0
+	for _, host := range r.Hosts {
+		ports := make([]report.Port, 0, len(host.Ports))
+		for _, port := range host.Ports {
+			findings := make([]report.Finding, 0, len(port.Findings))
+			for _, f := range port.Findings {
+				findings = append(findings, report.Finding{
+					Name:     f.Name,
+					Severity: f.Severity,
+					Evidence: f.Evidence,
+					CVE:      f.CVE,
+				})
+			}
+			ports = append(ports, report.Port{
+				Port:     port.Port,
+				State:    port.State,
+				Service:  port.Service,
+				Findings: findings,
+			})
+		}
+		totalPorts += len(ports)
+
+		creds := make([]report.Credential, 0, len(host.Credentials))
+		for _, c := range host.Credentials {
+			creds = append(creds, report.Credential{
+				Service:  c.Service,
+				Port:     c.Port,
+				Username: c.Username,
+				Password: c.Password,
+			})
+		}
+
+		hosts = append(hosts, report.Host{
+			Host:        host.Host,
+			Status:      host.Status,
+			Ports:       ports,
+			Credentials: creds,
+			OSMatches:   host.OSMatches,
+			Sources:     host.Sources,
+		})
+	}
+
+	vulns := make([]report.Vulnerability, 0, len(r.Vulnerabilities))
+	for _, v := range r.Vulnerabilities {
+		vulns = append(vulns, report.Vulnerability{
+			ID:        v.ID,
+			Severity:  v.Severity,
+			Host:      v.Host,
+			Port:      v.Port,
+			MatchedAt: v.MatchedAt,
+			Extracted: v.Extracted,
+		})
+	}
+
+	return report.Document{
+		ScanInfo: report.ScanInfo{
+			Targets:       r.ScanInfo.Targets,
+			StartTime:     r.ScanInfo.StartTime,
+			EndTime:       endTime,
+			Scanner:       r.ScanInfo.Scanner,
+			DiscoveryMode: r.ScanInfo.DiscoveryMode,
+			ResumeToken:   r.ScanInfo.ResumeToken,
+		},
+		Hosts: hosts,
+		Summary: report.Summary{
+			TotalHosts:           len(hosts),
+			TotalPorts:           totalPorts,
+			UnresponsiveHosts:    r.UnresponsiveHosts,
+			TotalVulnerabilities: len(vulns),
+		},
+		Vulnerabilities: vulns,
+	}
+}
+
+// PDive2 represents the main scanner configuration
+type PDive2 struct {
+	Targets       []string
+	OutputDir     string
+	Threads       int
+	DiscoveryMode string
+	Results       *ScanResults
+	EnableNmap    bool
+	NmapScripts   string // NSE script categories/names, e.g. "default,vuln,safe"
+	NmapTiming    string // timing template, e.g. "T4"
+	NmapArgs      []string
+	OutputFormats []string
+	ScanMode      string // "connect", "masscan", or "syn"
+	ScanRate      int    // packets/sec for the native SYN scanner
+
+	TemplatesDir string // extra vuln.Template dir, on top of the built-in set; empty uses built-ins only
+	MinSeverity  vuln.Severity
+
+	// IncludeSources/ExcludeSources filter which passive.Source
+	// implementations PassiveDiscovery runs, by Source.Name(). An empty
+	// IncludeSources means "every available source".
+	IncludeSources []string
+	ExcludeSources []string
+
+	EnableBrute         bool
+	BruteUsers          []string
+	BrutePasswords      []string
+	BruteUserPass       []bruteforce.Credential
+	BruteConcurrency    int
+	BruteLockoutAfter   int
+	BruteLockoutBackoff time.Duration
+	BruteTimeout        time.Duration
+	AllowPrivate        bool
+	IHaveAuthorization  bool
+
+	StatePath string // path to the bbolt state file; empty disables persistence
+	Resume    bool   // load StatePath and skip already-completed work
+
+	store      *state.Store
+	progress   *state.Progress
+	progressMu sync.Mutex
+
+	// Bus receives discovery events as HostDiscovery/PortScan/
+	// scanHostPorts find things, for --serve's SSE stream and metrics
+	// collector. Nil (the default) disables publishing.
+	Bus *api.Bus
+}
+
+// publish fans e out on p.Bus, or does nothing if --serve wasn't given.
+func (p *PDive2) publish(e api.Event) {
+	if p.Bus == nil {
+		return
+	}
+	e.Time = time.Now()
+	p.Bus.Publish(e)
+}
+
+// NewPDive2 creates a new PDive2 instance
+func NewPDive2(targets []string, outputDir string, threads int, discoveryMode string) *PDive2 {
+	return &PDive2{
+		Targets:             targets,
+		OutputDir:           outputDir,
+		Threads:             threads,
+		DiscoveryMode:       discoveryMode,
+		OutputFormats:       []string{"txt", "csv"},
+		ScanMode:            "masscan",
+		ScanRate:            1000,
+		BruteConcurrency:    10,
+		BruteLockoutAfter:   5,
+		BruteLockoutBackoff: 2 * time.Second,
+		BruteTimeout:        5 * time.Minute,
+		MinSeverity:         vuln.SeverityInfo,
+		Results: &ScanResults{
+			ScanInfo: ScanInfo{
+				Targets:       targets,
+				StartTime:     time.Now(),
+				Scanner:       fmt.Sprintf("PDive2 v%s", Version),
+				DiscoveryMode: discoveryMode,
+			},
+			Hosts: make([]HostInfo, 0),
+		},
+	}
+}
+
+// initState opens p.StatePath and either loads a previously checkpointed
+// Progress (when --resume is set and one exists) or starts a fresh one.
+// It is a no-op when StatePath is empty (persistence disabled).
+func (p *PDive2) initState() error {
+	if p.StatePath == "" {
+		return nil
+	}
+
+	if dir := filepath.Dir(p.StatePath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory for state file %s: %w", p.StatePath, err)
+		}
+	}
+
+	store, err := state.Open(p.StatePath)
+	if err != nil {
+		return err
+	}
+	p.store = store
+
+	if p.Resume {
+		progress, err := store.Load()
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			p.progress = progress
+			p.Results.ScanInfo.ResumeToken = progress.ResumeToken
+			log.Infof("main", "Resuming scan %s from %s", progress.ResumeToken, p.StatePath)
+			return nil
+		}
+		log.Warnf("main", "--resume given but %s has no saved progress, starting fresh", p.StatePath)
+	}
+
+	p.progress = state.NewProgress()
+	p.Results.ScanInfo.ResumeToken = p.progress.ResumeToken
+	return nil
+}
+
+// saveState checkpoints the current progress to disk. It is a no-op when
+// persistence is disabled.
+func (p *PDive2) saveState() {
+	if p.store == nil || p.progress == nil {
+		return
+	}
+	if err := p.store.Save(p.progress); err != nil {
+		log.Errorf("main", "Failed to save scan state: %v", err)
+	}
+}
+
+// Colors for output
+var (
+	cyan   = color.New(color.FgCyan)
+	yellow = color.New(color.FgYellow)
+	green  = color.New(color.FgGreen)
+	red    = color.New(color.FgRed)
+)
+
+// PrintBanner prints the application banner
+func (p *PDive2) PrintBanner() {
+	targetsDisplay := strings.Join(p.Targets[:min(3, len(p.Targets))], ", ")
+	if len(p.Targets) > 3 {
+		targetsDisplay += fmt.Sprintf(" ... (+%d more)", len(p.Targets)-3)
+	}
+
+	cyan.Print(Banner)
+	yellow.Println("Dive deep into the network")
+	red.Println("For authorized security testing only!")
+	fmt.Println()
+
+	fmt.Printf("Targets (%d): %s\n", len(p.Targets), green.Sprintf(targetsDisplay))
+	fmt.Printf("Output Directory: %s\n", green.Sprint(p.OutputDir))
+	fmt.Printf("Threads: %s\n", green.Sprint(p.Threads))
+	fmt.Printf("Discovery Mode: %s\n", green.Sprint(strings.ToUpper(p.DiscoveryMode)))
+	fmt.Println()
+}
+
+// ValidateTargets validates if all targets are valid IP addresses, network ranges, or hostnames
+func (p *PDive2) ValidateTargets() bool {
+	var validTargets []string
+	var invalidTargets []string
+
+	for _, target := range p.Targets {
+		if isValidTarget(target) {
+			validTargets = append(validTargets, target)
+		} else {
+			invalidTargets = append(invalidTargets, target)
+		}
+	}
+
+	if len(invalidTargets) > 0 {
+		red.Printf("[-] Invalid targets: %s\n", strings.Join(invalidTargets, ", "))
+	}
+
+	p.Targets = validTargets
+	return len(validTargets) > 0
+}
+
+// isValidTarget checks if a target is a valid IP, CIDR, or hostname
+func isValidTarget(target string) bool {
+	// Try parsing as IP/CIDR
+	if _, _, err := net.ParseCIDR(target); err == nil {
+		return true
+	}
+	if net.ParseIP(target) != nil {
+		return true
+	}
+
+	// Try resolving as hostname
+	if _, err := net.LookupHost(target); err == nil {
+		return true
+	}
+
+	return false
+}
+
+// expandTargets expands CIDR ranges to individual IPs
+func (p *PDive2) expandTargets() []string {
+	var allHosts []string
+
+	for _, target := range p.Targets {
+		if strings.Contains(target, "/") {
+			// CIDR range
+			if ip, ipnet, err := net.ParseCIDR(target); err == nil {
+				for ip := ip.Mask(ipnet.Mask); ipnet.Contains(ip); inc(ip) {
+					allHosts = append(allHosts, ip.String())
+				}
+			}
+		} else {
+			allHosts = append(allHosts, target)
+		}
+	}
+
+	return removeDuplicates(allHosts)
+}
+
+// inc increments an IP address
+func inc(ip net.IP) {
+	for j := len(ip) - 1; j >= 0; j-- {
+		ip[j]++
+		if ip[j] > 0 {
+			break
+		}
+	}
+}
+
+// removeDuplicates removes duplicate strings from a slice
+func removeDuplicates(slice []string) []string {
+	keys := make(map[string]bool)
+	var result []string
+
+	for _, item := range slice {
+		if !keys[item] {
+			keys[item] = true
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// mergeUnique combines a and b, dropping duplicates.
+func mergeUnique(a, b []string) []string {
+	return removeDuplicates(append(append([]string{}, a...), b...))
+}
+
+// excludeKnown drops any host already present in one of the known lists
+// (e.g. a previous run's confirmed-up or confirmed-down hosts).
+func excludeKnown(hosts []string, known ...[]string) []string {
+	seen := make(map[string]bool)
+	for _, list := range known {
+		for _, h := range list {
+			seen[h] = true
+		}
+	}
+
+	var remaining []string
+	for _, h := range hosts {
+		if !seen[h] {
+			remaining = append(remaining, h)
+		}
+	}
+	return remaining
+}
+
+// restoreHosts ensures each of hosts has a HostInfo entry in p.Results,
+// for hosts whose discovery was already checkpointed in a previous run.
+func (p *PDive2) restoreHosts(hosts []string, status string) {
+	p.Results.mutex.Lock()
+	defer p.Results.mutex.Unlock()
+
+	existing := make(map[string]bool, len(p.Results.Hosts))
+	for _, h := range p.Results.Hosts {
+		existing[h.Host] = true
+	}
+	for _, host := range hosts {
+		if existing[host] {
+			continue
+		}
+		p.Results.Hosts = append(p.Results.Hosts, HostInfo{Host: host, Status: status, Ports: make([]PortInfo, 0)})
+		existing[host] = true
+	}
+}
+
+// filterPortScanDone drops hosts already checkpointed as port-scanned in
+// a previous run (by any of the connect/masscan/syn backends).
+func (p *PDive2) filterPortScanDone(hosts []string) []string {
+	if p.progress == nil {
+		return hosts
+	}
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+
+	var remaining []string
+	for _, h := range hosts {
+		if !p.progress.PortScanDone[h] {
+			remaining = append(remaining, h)
+		}
+	}
+	return remaining
+}
+
+// markPortScanDone checkpoints that host's port scan has completed.
+func (p *PDive2) markPortScanDone(host string) {
+	if p.progress == nil {
+		return
+	}
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+	p.progress.PortScanDone[host] = true
+}
+
+// filterServiceEnumDone drops hosts already checkpointed as service-enumerated
+// in a previous run.
+func (p *PDive2) filterServiceEnumDone(hosts []string) []string {
+	if p.progress == nil {
+		return hosts
+	}
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+
+	var remaining []string
+	for _, h := range hosts {
+		if !p.progress.ServiceEnumDone[h] {
+			remaining = append(remaining, h)
+		}
+	}
+	return remaining
+}
+
+// markServiceEnumDone checkpoints that host's service enumeration has
+// completed.
+func (p *PDive2) markServiceEnumDone(host string) {
+	if p.progress == nil {
+		return
+	}
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+	p.progress.ServiceEnumDone[host] = true
+}
+
+// filterNmapDone drops hosts already checkpointed as nmap-scanned in a
+// previous run.
+func (p *PDive2) filterNmapDone(hosts []string) []string {
+	if p.progress == nil {
+		return hosts
+	}
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+
+	var remaining []string
+	for _, h := range hosts {
+		if !p.progress.NmapDone[h] {
+			remaining = append(remaining, h)
+		}
+	}
+	return remaining
+}
+
+// markNmapDone checkpoints that host's nmap scan has completed.
+func (p *PDive2) markNmapDone(host string) {
+	if p.progress == nil {
+		return
+	}
+	p.progressMu.Lock()
+	defer p.progressMu.Unlock()
+	p.progress.NmapDone[host] = true
+}
+
+// min returns the minimum of two integers
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// HostDiscovery performs host discovery using ping and port-based detection
+func (p *PDive2) HostDiscovery(ctx context.Context) []string {
+	log.Infof("disc", "Starting host discovery...")
+
+	allHosts := p.expandTargets()
+
+	if p.progress != nil && p.progress.HostDiscoveryDone {
+		log.Infof("disc", "Host discovery already completed in a previous run, skipping (%d hosts up)", len(p.progress.HostsUp))
+		p.restoreHosts(p.progress.HostsUp, "up")
+		return p.progress.HostsUp
+	}
+
+	liveHosts := make(map[string]bool)
+	var mu sync.Mutex
+
+	if p.progress != nil {
+		for _, h := range p.progress.HostsUp {
+			liveHosts[h] = true
+		}
+		allHosts = excludeKnown(allHosts, p.progress.HostsUp, p.progress.HostsDown)
+	}
+
+	// Common ports for host discovery fallback
+	discoveryPorts := []int{80, 443, 22, 21, 25, 53, 135, 139, 445}
+
+	// Phase 1: Ping discovery
+	log.Infof("disc", "Phase 1: ping discovery...")
+	var wg sync.WaitGroup
+	hostChan := make(chan string, len(allHosts))
+
+	for _, host := range allHosts {
+		hostChan <- host
+	}
+	close(hostChan)
+
+	// Start ping workers
+	for i := 0; i < p.Threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range hostChan {
+				if ctx.Err() != nil {
+					continue
+				}
+				if p.pingHost(host) {
+					mu.Lock()
+					liveHosts[host] = true
+					mu.Unlock()
+					log.Debugf("disc", "host discovered (ping): %s", host)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Phase 2: Port-based discovery for non-ping responsive hosts
+	var nonPingHosts []string
+	for _, host := range allHosts {
+		if !liveHosts[host] {
+			nonPingHosts = append(nonPingHosts, host)
+		}
+	}
+
+	if len(nonPingHosts) > 0 && ctx.Err() == nil {
+		log.Infof("disc", "Phase 2: port-based discovery for %d non-ping responsive hosts...", len(nonPingHosts))
+
+		hostChan = make(chan string, len(nonPingHosts))
+		for _, host := range nonPingHosts {
+			hostChan <- host
+		}
+		close(hostChan)
+
+		// Start port discovery workers
+		for i := 0; i < min(p.Threads, 20); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for host := range hostChan {
+					if ctx.Err() != nil {
+						continue
+					}
+					if p.portDiscovery(host, discoveryPorts) {
+						mu.Lock()
+						liveHosts[host] = true
+						mu.Unlock()
+						log.Debugf("disc", "host discovered (port): %s", host)
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+	}
+
+	// Phase 3: mDNS/DNS-SD and NetBIOS discovery for hosts that still
+	// haven't answered ping or a TCP connect.
+	localInfo := make(map[string]localHostInfo)
+	var stillDown []string
+	for _, host := range allHosts {
+		if !liveHosts[host] {
+			stillDown = append(stillDown, host)
+		}
+	}
+
+	if len(stillDown) > 0 && ctx.Err() == nil {
+		log.Infof("disc", "Phase 3: mDNS/NetBIOS discovery for %d remaining hosts...", len(stillDown))
+		p.localLinkDiscovery(stillDown, liveHosts, localInfo, &mu)
+	}
+
+	// Convert map to slice
+	var liveHostsList []string
+	for host := range liveHosts {
+		liveHostsList = append(liveHostsList, host)
+	}
+
+	// Update results
+	p.Results.mutex.Lock()
+	for _, host := range liveHostsList {
+		info := HostInfo{
+			Host:   host,
+			Status: "up",
+			Ports:  make([]PortInfo, 0),
+		}
+		if l, ok := localInfo[host]; ok {
+			info.Hostname = l.Hostname
+			info.MAC = l.MAC
+			info.Services = l.Services
+		}
+		p.Results.Hosts = append(p.Results.Hosts, info)
+	}
+	p.Results.UnresponsiveHosts = len(allHosts) - len(liveHostsList)
+	p.Results.mutex.Unlock()
+
+	for _, host := range liveHostsList {
+		p.publish(api.Event{Type: api.EventHostDiscovered, Host: host})
+	}
+
+	if p.progress != nil {
+		p.progress.HostsUp = liveHostsList
+		if ctx.Err() == nil {
+			var down []string
+			for _, host := range allHosts {
+				if !liveHosts[host] {
+					down = append(down, host)
+				}
+			}
+			p.progress.HostsDown = mergeUnique(p.progress.HostsDown, down)
+			p.progress.HostDiscoveryDone = true
+		}
+		p.saveState()
+	}
+
+	if ctx.Err() != nil {
+		log.Warnf("disc", "Host discovery interrupted; checkpointed %d live hosts for resume", len(liveHostsList))
+		return liveHostsList
+	}
+
+	log.Infof("disc", "Host discovery completed. Found %d live hosts from %d total hosts.",
+		len(liveHostsList), len(allHosts))
+	log.Infof("disc", "Ping responsive: %d, Port responsive: %d",
+		len(liveHosts)-len(nonPingHosts), len(liveHostsList)-(len(liveHosts)-len(nonPingHosts)))
+
+	return liveHostsList
+}
+
+// pingHost performs a ping test on a host
+func (p *PDive2) pingHost(host string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "ping", "-c", "1", "-W", "2", host)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	start := time.Now()
+	up := cmd.Run() == nil
+	if up {
+		p.publish(api.Event{Type: api.EventPingRTT, Host: host, RTT: time.Since(start).Seconds()})
+	}
+	return up
+}
+
+// portDiscovery tries to connect to common ports to detect live hosts
+func (p *PDive2) portDiscovery(host string, ports []int) bool {
+	for _, port := range ports {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), 3*time.Second)
+		if err == nil {
+			conn.Close()
+			return true
+		}
+	}
+	return false
+}
+
+const mdnsDwell = 3 * time.Second
+
+// localHostInfo carries the hostname/MAC/service data recovered for a host
+// by mDNS/DNS-SD browsing or a NetBIOS NBSTAT query.
+type localHostInfo struct {
+	Hostname string
+	MAC      string
+	Services []string
+}
+
+// localLinkDiscovery enriches (and where possible revives) hosts that
+// didn't answer ping or a TCP connect, via active mDNS/DNS-SD browsing and
+// unicast NetBIOS NBSTAT queries. It degrades gracefully: a failed mDNS
+// browse (no IPv6, unprivileged socket, ...) just means fewer hosts get
+// upgraded to "up" here, not an error for the caller.
+func (p *PDive2) localLinkDiscovery(hosts []string, liveHosts map[string]bool, localInfo map[string]localHostInfo, mu *sync.Mutex) {
+	mdnsHosts, err := discovery.BrowseMDNS(mdnsDwell)
+	if err != nil {
+		log.Warnf("disc", "mDNS browse failed: %v", err)
+	}
+
+	mdnsByAddr := make(map[string]discovery.Host, len(mdnsHosts))
+	for _, h := range mdnsHosts {
+		mdnsByAddr[h.Address] = h
+	}
+
+	mu.Lock()
+	for _, host := range hosts {
+		if m, ok := mdnsByAddr[host]; ok {
+			liveHosts[host] = true
+			localInfo[host] = localHostInfo{Hostname: m.Hostname, Services: m.Services}
+			log.Debugf("disc", "host discovered (mDNS): %s (%s)", host, m.Hostname)
+		}
+	}
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		host := host
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := discovery.QueryNetBIOS(host, 2*time.Second)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			liveHosts[host] = true
+			entry := localInfo[host]
+			if entry.Hostname == "" && len(info.Names) > 0 {
+				entry.Hostname = info.Names[0]
+			}
+			entry.MAC = info.MAC
+			localInfo[host] = entry
+			log.Debugf("disc", "host discovered (NetBIOS): %s (%s)", host, entry.Hostname)
+		}()
+	}
+	wg.Wait()
+}
+
+// PortScan performs port scanning on discovered hosts
+func (p *PDive2) PortScan(ctx context.Context, hosts []string) {
+	log.Infof("port", "Starting port scanning...")
+
+	hosts = p.filterPortScanDone(hosts)
+	if len(hosts) == 0 {
+		log.Infof("port", "All hosts already port-scanned in a previous run, skipping")
+		return
+	}
+
+	commonPorts := []int{21, 22, 23, 25, 53, 80, 110, 111, 135, 139, 143, 443, 993, 995, 1723, 3306, 3389, 5432, 5900, 8080, 8443}
+
+	var wg sync.WaitGroup
+	hostChan := make(chan string, len(hosts))
+
+	for _, host := range hosts {
+		hostChan <- host
+	}
+	close(hostChan)
+
+	// Start port scanning workers
+	for i := 0; i < p.Threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for host := range hostChan {
+				if ctx.Err() != nil {
+					continue
+				}
+				p.scanHostPorts(ctx, host, commonPorts)
+				p.markPortScanDone(host)
+			}
+		}()
+	}
+
+	wg.Wait()
+	p.saveState()
+}
+
+// scanHostPorts scans ports for a specific host
+func (p *PDive2) scanHostPorts(ctx context.Context, host string, ports []int) {
+	if ctx.Err() != nil {
+		return
+	}
+	log.Debugf("port", "scanning %s...", host)
+	var openPorts []PortInfo
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	portChan := make(chan int, len(ports))
+
+	for _, port := range ports {
+		portChan <- port
+	}
+	close(portChan)
+
+	// Start port workers
+	for i := 0; i < min(p.Threads, 50); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for port := range portChan {
+				if p.scanPort(host, port) {
+					mu.Lock()
+					openPorts = append(openPorts, PortInfo{
+						Port:    port,
+						State:   "open",
+						Service: "",
+					})
+					mu.Unlock()
+					log.Debugf("port", "open port found: %s:%d", host, port)
+					p.publish(api.Event{Type: api.EventPortOpen, Host: host, Port: port})
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Update results
+	p.Results.mutex.Lock()
+	for i := range p.Results.Hosts {
+		if p.Results.Hosts[i].Host == host {
+			p.Results.Hosts[i].Ports = openPorts
+			break
+		}
+	}
+	p.Results.mutex.Unlock()
+}
+
+// scanPort scans a specific port on a host
+func (p *PDive2) scanPort(host string, port int) bool {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%d", host, port), 3*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// ServiceEnumeration performs service enumeration on open ports
+func (p *PDive2) ServiceEnumeration(ctx context.Context, hosts []string) {
+	log.Infof("svc", "Starting service enumeration...")
+
+	hosts = p.filterServiceEnumDone(hosts)
+
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			log.Warnf("svc", "Service enumeration interrupted; checkpointing progress for resume")
+			break
+		}
+
+		p.Results.mutex.Lock()
+		var hostIndex int = -1
+		for i, h := range p.Results.Hosts {
+			if h.Host == host {
+				hostIndex = i
+				break
+			}
+		}
+
+		if hostIndex != -1 {
+			for j, port := range p.Results.Hosts[hostIndex].Ports {
+				service, findings := p.enumerateService(host, port.Port)
+				p.Results.Hosts[hostIndex].Ports[j].Service = service
+				p.Results.Hosts[hostIndex].Ports[j].Findings = findings
+				log.Debugf("svc", "service identified: %s:%d -> %s", host, port.Port, service)
+				for _, f := range findings {
+					log.Warnf("svc", "%s:%d -> %s (%s)", host, port.Port, f.Name, f.Severity)
+				}
+			}
+		}
+		p.Results.mutex.Unlock()
+		p.markServiceEnumDone(host)
+	}
+
+	p.saveState()
+}
+
+// NmapScan runs nmap's -sV/-sC/-O fingerprinting against every host in
+// masscanResults (one nmap invocation per host, restricted to the ports
+// masscan already found open), merging service, NSE script, and OS
+// findings into p.Results. Concurrency is governed by p.Threads, like
+// ServiceEnumeration's worker pool.
+func (p *PDive2) NmapScan(ctx context.Context, masscanResults map[string][]PortInfo) {
+	scanner := nmap.NewNmapScanner(nmap.Config{
+		Scripts:   p.NmapScripts,
+		Timing:    p.NmapTiming,
+		ExtraArgs: p.NmapArgs,
+	})
+
+	type nmapJob struct {
+		host  string
+		ports []int
+	}
+	var jobs []nmapJob
+	for _, host := range p.filterNmapDone(hostsWithPorts(masscanResults)) {
+		var portNums []int
+		for _, pi := range masscanResults[host] {
+			portNums = append(portNums, pi.Port)
+		}
+		if len(portNums) > 0 {
+			jobs = append(jobs, nmapJob{host: host, ports: portNums})
+		}
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	log.Infof("nmap", "Running nmap against %d hosts...", len(jobs))
+
+	jobChan := make(chan nmapJob, len(jobs))
+	for _, j := range jobs {
+		jobChan <- j
+	}
+	close(jobChan)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(p.Threads, len(jobs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobChan {
+				if ctx.Err() != nil {
+					continue
+				}
+				host, err := scanner.Scan(ctx, j.host, j.ports)
+				if err != nil {
+					log.Warnf("nmap", "nmap failed for %s: %v", j.host, err)
+					continue
+				}
+				p.mergeNmapHost(j.host, host)
+				p.markNmapDone(j.host)
+			}
+		}()
+	}
+	wg.Wait()
+	p.saveState()
+
+	log.Infof("nmap", "Nmap scan completed.")
+}
+
+// hostsWithPorts returns the hosts in found that have at least one
+// recorded port, in no particular order.
+func hostsWithPorts(found map[string][]PortInfo) []string {
+	hosts := make([]string, 0, len(found))
+	for host, ports := range found {
+		if len(ports) > 0 {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// mergeNmapHost folds one host's nmap result into p.Results: port
+// services are refined with nmap's -sV product/version, NSE script
+// output becomes Findings, and OS guesses populate HostInfo.OSMatches.
+func (p *PDive2) mergeNmapHost(host string, info nmap.Host) {
+	p.Results.mutex.Lock()
+	defer p.Results.mutex.Unlock()
+
+	for i := range p.Results.Hosts {
+		if p.Results.Hosts[i].Host != host {
+			continue
+		}
+
+		var osMatches []string
+		for _, m := range info.OSMatches {
+			osMatches = append(osMatches, fmt.Sprintf("%s (%d%%)", m.Name, m.Accuracy))
+		}
+		p.Results.Hosts[i].OSMatches = osMatches
+
+		for _, np := range info.Ports {
+			for j, existing := range p.Results.Hosts[i].Ports {
+				if existing.Port != np.Number {
+					continue
+				}
+
+				if service := formatNmapService(np.Service); service != "" {
+					p.Results.Hosts[i].Ports[j].Service = service
+				}
+
+				for _, sc := range np.Scripts {
+					severity := "info"
+					if strings.Contains(sc.ID, "vuln") {
+						severity = "medium"
+					}
+					p.Results.Hosts[i].Ports[j].Findings = append(p.Results.Hosts[i].Ports[j].Findings, Finding{
+						Name:     sc.ID,
+						Severity: severity,
+						Evidence: sc.Output,
+					})
+					log.Debugf("nmap", "%s:%d -> %s", host, np.Number, sc.ID)
+				}
+			}
+		}
+
+		return
+	}
+}
+
+// formatNmapService renders an nmap Service as the same
+// "name (product version)" style main.go already uses for serviceprobe
+// results.
+func formatNmapService(svc nmap.Service) string {
+	if svc.Name == "" {
+		return ""
+	}
+	if svc.Product == "" {
+		return svc.Name
+	}
+	if svc.Version == "" {
+		return fmt.Sprintf("%s (%s)", svc.Name, svc.Product)
+	}
+	return fmt.Sprintf("%s (%s %s)", svc.Name, svc.Product, svc.Version)
+}
+
+// VulnerabilityScan runs vuln.Engine's Nuclei-style templates (the
+// built-in set, plus --templates if given) against every host:port pair
+// in hosts whose service has been identified, merging confirmed matches
+// into p.Results.Vulnerabilities. Concurrency is governed by p.Threads,
+// like ServiceEnumeration's worker pool.
+func (p *PDive2) VulnerabilityScan(ctx context.Context, hosts []string) {
+	templates, err := vuln.Builtins()
+	if err != nil {
+		log.Errorf("vuln", "Failed to load built-in templates: %v", err)
+		return
+	}
+	if p.TemplatesDir != "" {
+		extra, err := vuln.LoadTemplates(p.TemplatesDir)
+		if err != nil {
+			log.Errorf("vuln", "Failed to load --templates %s: %v", p.TemplatesDir, err)
+		} else {
+			templates = append(templates, extra...)
+		}
+	}
+
+	minSeverity := p.MinSeverity
+	if minSeverity == "" {
+		minSeverity = vuln.SeverityInfo
+	}
+	engine := vuln.NewEngine(templates, minSeverity)
+
+	type vulnTarget struct {
+		host string
+		port int
+	}
+	wanted := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		wanted[h] = true
+	}
+
+	var targets []vulnTarget
+	p.Results.mutex.RLock()
+	for _, h := range p.Results.Hosts {
+		if !wanted[h.Host] {
+			continue
+		}
+		for _, port := range h.Ports {
+			if port.Service == "" || port.Service == "unknown" {
+				continue
+			}
+			targets = append(targets, vulnTarget{host: h.Host, port: port.Port})
+		}
+	}
+	p.Results.mutex.RUnlock()
+
+	if len(targets) == 0 {
+		log.Infof("vuln", "No identified services to check, skipping vulnerability scan.")
+		return
+	}
+
+	log.Infof("vuln", "Running %d templates against %d host:port targets...", len(templates), len(targets))
+
+	targetChan := make(chan vulnTarget, len(targets))
+	for _, t := range targets {
+		targetChan <- t
+	}
+	close(targetChan)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(p.Threads, len(targets)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range targetChan {
+				if ctx.Err() != nil {
+					continue
+				}
+				for _, f := range engine.Scan(ctx, t.host, t.port) {
+					log.Warnf("vuln", "%s:%d -> [%s] %s", t.host, t.port, f.Severity, f.TemplateID)
+					p.Results.mutex.Lock()
+					p.Results.Vulnerabilities = append(p.Results.Vulnerabilities, Vulnerability{
+						ID:        f.TemplateID,
+						Severity:  string(f.Severity),
+						Host:      f.Host,
+						Port:      f.Port,
+						MatchedAt: f.MatchedAt,
+						Extracted: f.Extracted,
+					})
+					p.Results.mutex.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	log.Infof("vuln", "Vulnerability scan completed.")
+}
+
+// BruteForce attempts credential-stuffing against every brute-forceable
+// service found during enumeration. It is opt-in (--brute) and enforces
+// the authorization interlock per host before touching it: a private/
+// loopback target requires --allow-private, a public one requires
+// --i-have-authorization.
+func (p *PDive2) BruteForce(hosts []string) {
+	log.Infof("brute", "Starting brute force (opt-in)...")
+
+	creds := bruteforce.BuildCredentials(p.BruteUsers, p.BrutePasswords, p.BruteUserPass)
+	if len(creds) == 0 {
+		log.Errorf("brute", "No credentials to try (need --users/--passwords or --userpass)")
+		return
+	}
+
+	cfg := bruteforce.Config{
+		Concurrency:      p.BruteConcurrency,
+		LockoutThreshold: p.BruteLockoutAfter,
+		LockoutBackoff:   p.BruteLockoutBackoff,
+		Timeout:          p.BruteTimeout,
+	}
+
+	for _, host := range hosts {
+		if err := bruteforce.Authorize(host, p.AllowPrivate, p.IHaveAuthorization); err != nil {
+			log.Warnf("brute", "Skipping brute-force of %s: %v", host, err)
+			continue
+		}
+
+		p.Results.mutex.RLock()
+		var targets []bruteforce.Target
+		for _, h := range p.Results.Hosts {
+			if h.Host != host {
+				continue
+			}
+			for _, port := range h.Ports {
+				service := baseServiceName(port.Service)
+				if bruteforce.Supported(service) {
+					targets = append(targets, bruteforce.Target{Host: host, Port: port.Port, Service: service})
+				}
+			}
+		}
+		p.Results.mutex.RUnlock()
+
+		if len(targets) == 0 {
+			continue
+		}
+
+		log.Infof("brute", "Brute-forcing %d service(s) on %s...", len(targets), host)
+		results := bruteforce.Run(targets, creds, cfg)
+
+		if len(results) == 0 {
+			continue
+		}
+
+		p.Results.mutex.Lock()
+		for i, h := range p.Results.Hosts {
+			if h.Host != host {
+				continue
+			}
+			for _, r := range results {
+				p.Results.Hosts[i].Credentials = append(p.Results.Hosts[i].Credentials, Credential{
+					Service:  r.Service,
+					Port:     r.Port,
+					Username: r.Username,
+					Password: r.Password,
+				})
+				log.Warnf("brute", "Valid credential found: %s:%d (%s) %s / %s", host, r.Port, r.Service, r.Username, r.Password)
+			}
+		}
+		p.Results.mutex.Unlock()
+	}
+}
+
+// baseServiceName strips the "(version/banner)" suffix enumerateService
+// adds to PortInfo.Service, leaving the bare serviceprobe/bruteforce
+// service name.
+func baseServiceName(service string) string {
+	if i := strings.Index(service, " ("); i >= 0 {
+		return service[:i]
+	}
+	return service
+}
+
+// enumerateService fingerprints the service on host:port via the
+// pluggable serviceprobe package, falling back to "unknown" when no
+// probe recognizes it.
+func (p *PDive2) enumerateService(host string, port int) (string, []Finding) {
+	info, probeFindings := serviceprobe.Identify(host, port)
+	if info.Name == "" {
+		return "unknown", nil
+	}
+
+	service := info.Name
+	if info.Version != "" {
+		service = fmt.Sprintf("%s (%s)", service, info.Version)
+	} else if info.Banner != "" {
+		service = fmt.Sprintf("%s (%s)", service, info.Banner)
+	}
+
+	findings := make([]Finding, 0, len(probeFindings))
+	for _, f := range probeFindings {
+		findings = append(findings, Finding{
+			Name:     f.Name,
+			Severity: f.Severity,
+			Evidence: f.Evidence,
+			CVE:      f.CVE,
+		})
+	}
+
+	return service, findings
+}
+
+// passiveSources returns every available passive.Source, in the order
+// they're listed for --sources.
+func (p *PDive2) passiveSources() []passive.Source {
+	cfg := passive.LoadConfig()
+	return []passive.Source{
+		passive.NewAmassSource(),
+		passive.NewCTSource(),
+		passive.NewShodanSource(cfg.Shodan),
+		passive.NewCensysSource(cfg.CensysID, cfg.CensysSecret),
+		passive.NewSecurityTrailsSource(cfg.SecurityTrails),
+		passive.NewVirusTotalSource(cfg.VirusTotal),
+		passive.NewOTXSource(cfg.AlienVaultOTX),
+		passive.NewHackerTargetSource(),
+		passive.NewDNSBruteSource(),
+	}
+}
+
+// PassiveDiscovery runs every selected passive.Source (see
+// --sources/--exclude-sources) against each target domain concurrently
+// and merges their results, recording which source(s) found each host.
+func (p *PDive2) PassiveDiscovery(ctx context.Context) []string {
+	if p.progress != nil && p.progress.PassiveDone {
+		log.Infof("disc", "Resuming: passive discovery already completed, reusing %d hosts.", len(p.progress.PassiveHosts))
+		p.restoreHosts(p.progress.PassiveHosts, "discovered")
+		return p.progress.PassiveHosts
+	}
+
+	sources, unknown := passive.Select(p.passiveSources(), p.IncludeSources, p.ExcludeSources)
+	for _, name := range unknown {
+		log.Warnf("disc", "Unknown passive source: %s", name)
+	}
+	log.Infof("disc", "Starting passive discovery across %d source(s)...", len(sources))
+
+	var discoveredHosts []string
+	hostSources := make(map[string]map[string]bool)
+
+	for _, target := range p.Targets {
+		if ctx.Err() != nil {
+			log.Warnf("disc", "Passive discovery cancelled, stopping early.")
+			break
+		}
+
+		domain := p.extractDomain(target)
+		if domain == "" {
+			continue
+		}
+
+		log.Infof("disc", "Performing passive discovery on domain: %s", domain)
+		for _, r := range passive.Run(ctx, domain, sources) {
+			if hostSources[r.Host] == nil {
+				hostSources[r.Host] = make(map[string]bool)
+				discoveredHosts = append(discoveredHosts, r.Host)
+			}
+			for _, src := range r.Sources {
+				hostSources[r.Host][src] = true
+			}
+		}
+	}
+
+	discoveredHosts = removeDuplicates(discoveredHosts)
+
+	// Add discovered hosts to results
+	p.Results.mutex.Lock()
+	for _, host := range discoveredHosts {
+		var srcs []string
+		for src := range hostSources[host] {
+			srcs = append(srcs, src)
+		}
+		sort.Strings(srcs)
+		p.Results.Hosts = append(p.Results.Hosts, HostInfo{
+			Host:    host,
+			Status:  "discovered",
+			Ports:   make([]PortInfo, 0),
+			Sources: srcs,
+		})
+	}
+	p.Results.mutex.Unlock()
+
+	log.Infof("disc", "Passive discovery completed. Found %d hosts.", len(discoveredHosts))
+
+	if ctx.Err() == nil && p.progress != nil {
+		p.progress.PassiveDone = true
+		p.progress.PassiveHosts = discoveredHosts
+	}
+	p.saveState()
+
+	return discoveredHosts
+}
+
+// LocalDiscovery performs local-link host discovery via active mDNS/DNS-SD
+// browsing and NetBIOS NBSTAT queries, for use with --mode local when a
+// target has no externally-facing infrastructure (e.g. scanning a LAN
+// segment with no DNS or masscan-reachable hosts).
+func (p *PDive2) LocalDiscovery() []string {
+	log.Infof("disc", "Starting local-link discovery (mDNS/NetBIOS)...")
+
+	mdnsHosts, err := discovery.BrowseMDNS(mdnsDwell)
+	if err != nil {
+		log.Warnf("disc", "mDNS browse failed: %v", err)
+	}
+
+	var discoveredHosts []string
+	p.Results.mutex.Lock()
+	for _, h := range mdnsHosts {
+		discoveredHosts = append(discoveredHosts, h.Address)
+		p.Results.Hosts = append(p.Results.Hosts, HostInfo{
+			Host:     h.Address,
+			Status:   "discovered",
+			Ports:    make([]PortInfo, 0),
+			Hostname: h.Hostname,
+			Services: h.Services,
+		})
+		log.Debugf("disc", "mDNS discovered: %s (%s)", h.Address, h.Hostname)
+	}
+	p.Results.mutex.Unlock()
+
+	log.Infof("disc", "Local-link discovery completed. Found %d hosts.", len(discoveredHosts))
+
+	return discoveredHosts
+}
+
+// extractDomain extracts domain name from target
+func (p *PDive2) extractDomain(target string) string {
+	// If it's an IP or CIDR, skip
+	if net.ParseIP(target) != nil {
+		return ""
+	}
+	if _, _, err := net.ParseCIDR(target); err == nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(target))
+}
+
+// MasscanScan performs fast port scanning using masscan
+func (p *PDive2) MasscanScan(ctx context.Context, hosts []string) map[string][]PortInfo {
+	log.Infof("masscan", "Starting fast port scan...")
+
+	hosts = p.filterPortScanDone(hosts)
+	if len(hosts) == 0 {
+		log.Infof("masscan", "All hosts already port-scanned in a previous run, skipping")
+		return make(map[string][]PortInfo)
+	}
+
+	if p.ScanMode == "syn" {
+		if results, ok := p.synScan(ctx, hosts); ok {
+			return results
+		}
+		log.Warnf("masscan", "Falling back to masscan/basic port scan...")
+	}
+
+	if ctx.Err() != nil {
+		return make(map[string][]PortInfo)
+	}
+
+	// --scan-mode connect skips both masscan and the native SYN scanner
+	// and goes straight to the plain connect-based PortScan, for
+	// environments where neither masscan nor CAP_NET_RAW is available.
+	if p.ScanMode == "connect" {
+		log.Infof("masscan", "Running connect-based port scan on %d hosts...", len(hosts))
+		p.PortScan(ctx, hosts)
+
+		results := make(map[string][]PortInfo)
+		p.Results.mutex.RLock()
+		for _, host := range p.Results.Hosts {
+			if len(host.Ports) > 0 {
+				results[host.Host] = host.Ports
+			}
+		}
+		p.Results.mutex.RUnlock()
+		return results
+	}
+
+	// Check if masscan is available
+	if _, err := exec.LookPath("masscan"); err != nil {
+		log.Warnf("masscan", "Masscan not found in PATH, falling back to basic port scan (install from: https://github.com/robertdavidgraham/masscan)")
+		p.PortScan(ctx, hosts)
+
+		// Convert results format
+		results := make(map[string][]PortInfo)
+		p.Results.mutex.RLock()
+		for _, host := range p.Results.Hosts {
+			if len(host.Ports) > 0 {
+				results[host.Host] = host.Ports
+			}
+		}
+		p.Results.mutex.RUnlock()
+		return results
+	}
+
+	masscanResults := make(map[string][]PortInfo)
+
+	// Create temporary target file for masscan
+	tmpfile, err := os.CreateTemp("", "masscan_targets_*.txt")
+	if err != nil {
+		log.Errorf("masscan", "Failed to create temp file: %v", err)
+		return masscanResults
+	}
+	defer os.Remove(tmpfile.Name())
+
+	for _, host := range hosts {
+		fmt.Fprintln(tmpfile, host)
+	}
+	tmpfile.Close()
+
+	log.Infof("masscan", "Running masscan on %d hosts...", len(hosts))
+
+	// Run masscan with output in list format; tied to ctx so Ctrl+C kills
+	// the subprocess promptly instead of waiting out the full timeout.
+	cmdCtx, cancel := context.WithTimeout(ctx, 300*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, "masscan", "-iL", tmpfile.Name(), "-p1-65535", "--rate", "1000", "--output-format", "list")
+	output, err := cmd.Output()
+
+	if err != nil {
+		log.Warnf("masscan", "Masscan failed: %v", err)
+		log.Warnf("masscan", "Falling back to basic port scan...")
+		p.PortScan(ctx, hosts)
+
+		// Convert results format
+		results := make(map[string][]PortInfo)
+		p.Results.mutex.RLock()
+		for _, host := range p.Results.Hosts {
+			if len(host.Ports) > 0 {
+				results[host.Host] = host.Ports
+			}
+		}
+		p.Results.mutex.RUnlock()
+		return results
+	}
+
+	// Parse masscan output
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			// Masscan list format: "open tcp 80 1.2.3.4 1234567890"
+			parts := strings.Fields(line)
+			if len(parts) >= 4 && parts[0] == "open" && parts[1] == "tcp" {
+				portStr := parts[2]
+				host := parts[3]
+
+				if port, err := strconv.Atoi(portStr); err == nil {
+					if _, exists := masscanResults[host]; !exists {
+						masscanResults[host] = make([]PortInfo, 0)
+					}
+					masscanResults[host] = append(masscanResults[host], PortInfo{
+						Port:    port,
+						State:   "open",
+						Service: "",
+					})
+
+					log.Debugf("masscan", "masscan found: %s:%s", host, portStr)
+				}
+			}
+		}
+	}
+
+	log.Infof("masscan", "Masscan completed. Found ports on %d hosts.", len(masscanResults))
+
+	p.mergePortResults(hosts, masscanResults)
+	for _, host := range hosts {
+		p.markPortScanDone(host)
+	}
+	p.saveState()
+
+	return masscanResults
+}
+
+// mergePortResults folds a host -> ports map produced by any of the fast
+// port scan backends (masscan, native SYN, connect fallback) into
+// p.Results, creating a HostInfo entry for any host not already present.
+func (p *PDive2) mergePortResults(hosts []string, found map[string][]PortInfo) {
+	p.Results.mutex.Lock()
+	defer p.Results.mutex.Unlock()
+
+	for _, host := range hosts {
+		hostIndex := -1
+		for i, h := range p.Results.Hosts {
+			if h.Host == host {
+				hostIndex = i
+				break
+			}
+		}
+
+		if hostIndex == -1 {
+			p.Results.Hosts = append(p.Results.Hosts, HostInfo{
+				Host:   host,
+				Status: "up",
+				Ports:  make([]PortInfo, 0),
+			})
+			hostIndex = len(p.Results.Hosts) - 1
+		}
+
+		if ports, exists := found[host]; exists {
+			p.Results.Hosts[hostIndex].Ports = append(p.Results.Hosts[hostIndex].Ports, ports...)
+		}
+	}
+}
+
+// synScan runs the native raw-socket SYN scanner against hosts. It
+// reports ok=false when the raw socket couldn't be opened (e.g. missing
+// CAP_NET_RAW), so the caller can fall back to masscan/connect scanning.
+func (p *PDive2) synScan(ctx context.Context, hosts []string) (map[string][]PortInfo, bool) {
+	if ctx.Err() != nil {
+		return nil, false
+	}
+
+	scanner, err := scan.NewSYNScanner(p.ScanRate)
+	if err != nil {
+		log.Warnf("masscan", "Native SYN scanner unavailable: %v", err)
+		return nil, false
+	}
+	defer scanner.Close()
+
+	ports := []int{21, 22, 23, 25, 53, 80, 110, 111, 135, 139, 143, 443, 993, 995, 1723, 3306, 3389, 5432, 5900, 8080, 8443}
+
+	log.Infof("masscan", "Running native SYN scan on %d hosts at %d pps...", len(hosts), p.ScanRate)
+
+	raw, err := scanner.Scan(ctx, hosts, ports, 2*time.Second)
+	if err != nil {
+		log.Warnf("masscan", "SYN scan failed: %v", err)
+		return nil, false
+	}
+
+	results := make(map[string][]PortInfo)
+	for host, ports := range raw {
+		for _, r := range ports {
+			if r.State != "open" {
+				continue
+			}
+			results[host] = append(results[host], PortInfo{Port: r.Port, State: r.State})
+			log.Debugf("masscan", "SYN scan found: %s:%d", host, r.Port)
+		}
+	}
+
+	log.Infof("masscan", "SYN scan completed. Found ports on %d hosts.", len(results))
+	p.mergePortResults(hosts, results)
+	for _, host := range hosts {
+		p.markPortScanDone(host)
+	}
+	p.saveState()
+
+	return results, true
+}
+
+// GenerateReport renders the full scan results in every format requested
+// via --output-format (text/csv/json/jsonl/sarif).
+func (p *PDive2) GenerateReport() {
+	yellow.Println("\n[+] Generating Reports...")
+
+	doc := p.Results.Snapshot(time.Now())
+	timestamp := time.Now().Format("20060102_150405")
+
+	paths, err := report.WriteAll(doc, p.OutputDir, "recon_report", p.OutputFormats, timestamp)
+	if err != nil {
+		red.Printf("[-] Failed to generate reports: %v\n", err)
+		return
+	}
+
+	green.Println("[+] Reports saved to:")
+	for _, path := range paths {
+		fmt.Printf("  - %s\n", path)
+	}
+}
+
+// GeneratePassiveReport renders the passive discovery results in every
+// format requested via --output-format.
+func (p *PDive2) GeneratePassiveReport() {
+	yellow.Println("\n[+] Generating Passive Discovery Report...")
+
+	doc := p.Results.Snapshot(time.Now())
+	timestamp := time.Now().Format("20060102_150405")
+
+	paths, err := report.WriteAll(doc, p.OutputDir, "passive_discovery", p.OutputFormats, timestamp)
+	if err != nil {
+		red.Printf("[-] Failed to generate reports: %v\n", err)
+		return
+	}
+
+	green.Println("[+] Passive discovery reports saved to:")
+	for _, path := range paths {
+		fmt.Printf("  - %s\n", path)
+	}
+}
+
+// RunScan executes complete reconnaissance scan. ctx is checked between
+// phases (and inside their worker loops) so a single Ctrl+C lets the
+// current phase checkpoint its progress and exit cleanly instead of
+// leaving the state store out of date.
+func (p *PDive2) RunScan(ctx context.Context) {
+	if !p.ValidateTargets() {
+		red.Println("[-] No valid targets found")
+		return
+	}
+
+	if err := p.initState(); err != nil {
+		red.Printf("[-] Failed to initialize scan state: %v\n", err)
+		return
+	}
+	if p.store != nil {
+		defer p.store.Close()
+	}
+
+	p.PrintBanner()
+
+	if p.DiscoveryMode == "passive" {
+		// Passive discovery mode - use passive techniques only
+		discoveredHosts := p.PassiveDiscovery(ctx)
+		if len(discoveredHosts) == 0 {
+			red.Println("[-] No hosts discovered through passive methods.")
+			return
+		}
+
+		// In passive mode, only return the list of discovered hosts
+		yellow.Println("\n[+] PASSIVE DISCOVERY RESULTS")
+		yellow.Println(strings.Repeat("=", 50))
+		cyan.Printf("Total hosts discovered: %d\n\n", len(discoveredHosts))
+
+		green.Println("Discovered hosts:")
+		sort.Strings(discoveredHosts)
+		for _, host := range discoveredHosts {
+			fmt.Println(host)
+		}
+
+		// Generate simple report for passive mode
+		p.GeneratePassiveReport()
+
+	} else if p.DiscoveryMode == "local" {
+		// Local-link discovery mode - mDNS/DNS-SD and NetBIOS only, no
+		// network access beyond the local segment required.
+		discoveredHosts := p.LocalDiscovery()
+		if len(discoveredHosts) == 0 {
+			red.Println("[-] No hosts discovered on the local link.")
+			return
+		}
+
+		yellow.Println("\n[+] LOCAL-LINK DISCOVERY RESULTS")
+		yellow.Println(strings.Repeat("=", 50))
+		cyan.Printf("Total hosts discovered: %d\n\n", len(discoveredHosts))
+
+		green.Println("Discovered hosts:")
+		sort.Strings(discoveredHosts)
+		for _, host := range discoveredHosts {
+			fmt.Println(host)
+		}
+
+		p.GeneratePassiveReport()
+
+	} else {
+		// Active discovery mode - amass -> host discovery -> masscan -> nmap
+		yellow.Println("\n[+] Starting Active Discovery Mode")
+		cyan.Println("[*] Phase 1: Passive subdomain discovery with amass")
+
+		// First, run amass to discover subdomains
+		amassHosts := p.PassiveDiscovery(ctx)
+
+		// Then do traditional host discovery
+		cyan.Println("\n[*] Phase 2: Host discovery and connectivity check")
+		liveHosts := p.HostDiscovery(ctx)
+
+		// Combine amass results with live host discovery
+		allDiscoveredHosts := removeDuplicates(append(amassHosts, liveHosts...))
+
+		if len(allDiscoveredHosts) == 0 {
+			red.Println("[-] No live hosts discovered.")
+			return
+		}
+
+		// Ensure all discovered hosts are initialized in results before proceeding
+		p.Results.mutex.Lock()
+		hostMap := make(map[string]bool)
+		for _, host := range p.Results.Hosts {
+			hostMap[host.Host] = true
+		}
+		for _, host := range allDiscoveredHosts {
+			if !hostMap[host] {
+				p.Results.Hosts = append(p.Results.Hosts, HostInfo{
+					Host:   host,
+					Status: "up",
+					Ports:  make([]PortInfo, 0),
+				})
+			}
+		}
+		p.Results.mutex.Unlock()
+
+		cyan.Println("\n[*] Phase 3: Fast port scanning with masscan")
+		// Use masscan for fast port discovery
+		masscanResults := p.MasscanScan(ctx, allDiscoveredHosts)
+
+		if p.EnableNmap && len(masscanResults) > 0 && ctx.Err() == nil {
+			cyan.Println("\n[*] Phase 4: Detailed service/OS fingerprinting with nmap")
+			p.NmapScan(ctx, masscanResults)
+		}
+
+		if len(masscanResults) > 0 && ctx.Err() == nil {
+			// Do basic service enumeration on masscan results
+			cyan.Println("\n[*] Phase 5: Basic service identification")
+			p.ServiceEnumeration(ctx, allDiscoveredHosts)
+
+			if ctx.Err() == nil {
+				cyan.Println("\n[*] Phase 6: Vulnerability assessment")
+				p.VulnerabilityScan(ctx, allDiscoveredHosts)
+			}
+
+			if p.EnableBrute && ctx.Err() == nil {
+				cyan.Println("\n[*] Phase 7: Credential brute-forcing")
+				p.BruteForce(allDiscoveredHosts)
+			}
+		}
+
+		// Generate full report for active mode, covering whatever was
+		// completed even if a phase above was cut short by cancellation.
+		p.GenerateReport()
+	}
+
+	if ctx.Err() != nil {
+		yellow.Println("\n[!] Reconnaissance scan interrupted; progress saved for --resume.")
+		return
+	}
+	green.Println("\n[+] Reconnaissance scan completed!")
+}
+
+// LoadTargetsFromFile loads targets from a text file, one per line
+func LoadTargetsFromFile(filePath string) ([]string, error) {
+	var targets []string
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("target file not found: %s", filePath)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		target := strings.TrimSpace(scanner.Text())
+		if target != "" && !strings.HasPrefix(target, "#") {
+			targets = append(targets, target)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading target file: %v", err)
+	}
+
+	return targets, nil
+}
+
+// findLastStateFile returns the most recently modified state.db found
+// one level below root (i.e. <root>/*/state.db), matching the layout
+// each scan produces under its own --output directory. It's the backing
+// implementation of --resume-last.
+func findLastStateFile(root string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(root, "*", "state.db"))
+	if err != nil {
+		return "", fmt.Errorf("failed to search for state files: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no state.db found under %s/*/", root)
+	}
+
+	var newest string
+	var newestMod time.Time
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestMod) {
+			newest = path
+			newestMod = info.ModTime()
+		}
+	}
+	if newest == "" {
+		return "", fmt.Errorf("no readable state.db found under %s/*/", root)
+	}
+	return newest, nil
+}
+
+// CLI command configuration
+var (
+	targetFlag         string
+	targetFileFlag     string
+	outputFlag         string
+	threadsFlag        int
+	modeFlag           string
+	nmapFlag           bool
+	outputFormatFlag   string
+	scanModeFlag       string
+	rateFlag           int
+	bruteFlag          bool
+	usersFlag          string
+	passwordsFlag      string
+	userpassFlag       string
+	bruteTimeoutFlag   int
+	allowPrivateFlag   bool
+	haveAuthFlag       bool
+	logLevelFlag       string
+	logFormatFlag      string
+	stateFlag          string
+	resumeFlag         string
+	resumeLastFlag     bool
+	serveFlag          string
+	nmapScriptsFlag    string
+	nmapTimingFlag     string
+	nmapArgsFlag       string
+	templatesFlag      string
+	severityFlag       string
+	sourcesFlag        string
+	excludeSourcesFlag string
+)
+
+func main() {
+	var rootCmd = &cobra.Command{
+		Use:   "pdive2",
+		Short: "PDIve2 - Automated Penetration Testing Discovery Tool (Go Edition)",
+		Long: `PDIve2 - Automated Penetration Testing Discovery Tool (Go Edition)
+Dive deep into the network - A defensive security tool for authorized network reconnaissance and vulnerability assessment.
+
+Examples:
+  pdive2 -t 192.168.1.0/24
+  pdive2 -t 10.0.0.1 --nmap
+  pdive2 -f targets.txt -o /tmp/scan_results -T 100
+  pdive2 -t "192.168.1.1,example.com,10.0.0.0/24"
+  pdive2 -t example.com -m passive
+  pdive2 -t testphp.vulnweb.com -m active --nmap`,
+		Version: Version,
+		Run: func(cmd *cobra.Command, args []string) {
+			logLevel, err := log.ParseLevel(logLevelFlag)
+			if err != nil {
+				red.Printf("[-] %v\n", err)
+				os.Exit(1)
+			}
+			log.SetLevel(logLevel)
+
+			if logFormatFlag != "text" && logFormatFlag != "json" {
+				red.Printf("[-] Unknown log format: %s\n", logFormatFlag)
+				os.Exit(1)
+			}
+			log.SetFormat(logFormatFlag)
+
+			// Validate mode and nmap combination
+			if (modeFlag == "passive" || modeFlag == "local") && nmapFlag {
+				red.Println("[-] Error: --nmap flag is not compatible with passive/local mode")
+				os.Exit(1)
+			}
+
+			var targets []string
+
+			if targetFileFlag != "" {
+				targets, err = LoadTargetsFromFile(targetFileFlag)
+				if err != nil {
+					red.Printf("[-] %v\n", err)
+					os.Exit(1)
+				}
+				if len(targets) == 0 {
+					red.Println("[-] No valid targets found in file")
+					os.Exit(1)
+				}
+			} else if targetFlag != "" {
+				if strings.Contains(targetFlag, ",") {
+					for _, t := range strings.Split(targetFlag, ",") {
+						t = strings.TrimSpace(t)
+						if t != "" {
+							targets = append(targets, t)
+						}
+					}
+				} else {
+					targets = []string{targetFlag}
+				}
+			} else {
+				red.Println("[-] Either -t or -f flag is required")
+				os.Exit(1)
+			}
+
+			red.Println("WARNING: This tool is for authorized security testing only!")
+			red.Println("Ensure you have proper permission before scanning any network.\n")
+
+			targetsDisplay := strings.Join(targets[:min(3, len(targets))], ", ")
+			if len(targets) > 3 {
+				targetsDisplay += fmt.Sprintf(" ... (+%d more)", len(targets)-3)
+			}
+
+			fmt.Printf("Targets to scan: %s\n", targetsDisplay)
+			fmt.Print("Do you have authorization to scan these targets? (y/N): ")
+
+			reader := bufio.NewReader(os.Stdin)
+			response, _ := reader.ReadString('\n')
+			response = strings.TrimSpace(strings.ToLower(response))
+
+			if response != "y" {
+				fmt.Println("Scan aborted.")
+				os.Exit(1)
+			}
+
+			var formats []string
+			for _, f := range strings.Split(outputFormatFlag, ",") {
+				f = strings.ToLower(strings.TrimSpace(f))
+				if f == "" {
+					continue
+				}
+				if !report.ValidFormat(f) {
+					red.Printf("[-] Unknown output format: %s\n", f)
+					os.Exit(1)
+				}
+				formats = append(formats, f)
+			}
+			if len(formats) == 0 {
+				red.Println("[-] No valid output formats specified")
+				os.Exit(1)
+			}
+
+			if scanModeFlag != "masscan" && scanModeFlag != "syn" && scanModeFlag != "connect" {
+				red.Printf("[-] Unknown scan mode: %s\n", scanModeFlag)
+				os.Exit(1)
+			}
+
+			minSeverity := vuln.SeverityInfo
+			if severityFlag != "" {
+				minSeverity = vuln.Severity(severityFlag)
+				switch minSeverity {
+				case vuln.SeverityInfo, vuln.SeverityLow, vuln.SeverityMedium, vuln.SeverityHigh, vuln.SeverityCritical:
+				default:
+					red.Printf("[-] Unknown severity: %s\n", severityFlag)
+					os.Exit(1)
+				}
+			}
+
+			statePath := stateFlag
+			resume := false
+			switch {
+			case resumeLastFlag:
+				found, err := findLastStateFile(".")
+				if err != nil {
+					red.Printf("[-] --resume-last: %v\n", err)
+					os.Exit(1)
+				}
+				statePath = found
+				resume = true
+			case resumeFlag != "":
+				statePath = resumeFlag
+				resume = true
+			case statePath == "":
+				statePath = filepath.Join(outputFlag, "state.db")
+			}
+
+			pdive := NewPDive2(targets, outputFlag, threadsFlag, modeFlag)
+			pdive.EnableNmap = nmapFlag
+			pdive.OutputFormats = formats
+			pdive.ScanMode = scanModeFlag
+			pdive.ScanRate = rateFlag
+			pdive.StatePath = statePath
+			pdive.Resume = resume
+			pdive.TemplatesDir = templatesFlag
+			pdive.MinSeverity = minSeverity
+			if sourcesFlag != "" {
+				pdive.IncludeSources = strings.Split(sourcesFlag, ",")
+			}
+			if excludeSourcesFlag != "" {
+				pdive.ExcludeSources = strings.Split(excludeSourcesFlag, ",")
+			}
+			pdive.NmapScripts = nmapScriptsFlag
+			pdive.NmapTiming = nmapTimingFlag
+			if nmapArgsFlag != "" {
+				pdive.NmapArgs = strings.Fields(nmapArgsFlag)
+			}
+
+			if serveFlag != "" {
+				bus := api.NewBus()
+				pdive.Bus = bus
+				metrics := api.NewMetrics(bus)
+				srv := api.NewServer(serveFlag, bus, metrics, func() report.Document {
+					return pdive.Results.Snapshot(time.Now())
+				})
+				go func() {
+					if err := srv.ListenAndServe(); err != nil {
+						log.Errorf("api", "API server stopped: %v", err)
+					}
+				}()
+			}
+
+			if bruteFlag {
+				pdive.EnableBrute = true
+				pdive.AllowPrivate = allowPrivateFlag
+				pdive.IHaveAuthorization = haveAuthFlag
+				pdive.BruteTimeout = time.Duration(bruteTimeoutFlag) * time.Second
+
+				if usersFlag != "" {
+					pdive.BruteUsers, err = bruteforce.LoadLines(usersFlag)
+					if err != nil {
+						red.Printf("[-] %v\n", err)
+						os.Exit(1)
+					}
+				}
+				if passwordsFlag != "" {
+					pdive.BrutePasswords, err = bruteforce.LoadLines(passwordsFlag)
+					if err != nil {
+						red.Printf("[-] %v\n", err)
+						os.Exit(1)
+					}
+				}
+				if userpassFlag != "" {
+					pdive.BruteUserPass, err = bruteforce.LoadUserPass(userpassFlag)
+					if err != nil {
+						red.Printf("[-] %v\n", err)
+						os.Exit(1)
+					}
+				}
+				if len(pdive.BruteUsers) == 0 || (len(pdive.BrutePasswords) == 0 && len(pdive.BruteUserPass) == 0) {
+					red.Println("[-] --brute requires --users and (--passwords or --userpass)")
+					os.Exit(1)
+				}
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				if _, ok := <-sigCh; ok {
+					log.Warnf("main", "Received interrupt, finishing current work and saving state...")
+					cancel()
+				}
+			}()
+			defer signal.Stop(sigCh)
+
+			pdive.RunScan(ctx)
+		},
+	}
+
+	rootCmd.Flags().StringVarP(&targetFlag, "target", "t", "", "Target IP address, hostname, CIDR range, or comma-separated list")
+	rootCmd.Flags().StringVarP(&targetFileFlag, "file", "f", "", "File containing targets (one per line)")
+	rootCmd.Flags().StringVarP(&outputFlag, "output", "o", "recon_output", "Output directory (default: recon_output)")
+	rootCmd.Flags().IntVarP(&threadsFlag, "threads", "T", 50, "Number of threads (default: 50)")
+	rootCmd.Flags().StringVarP(&modeFlag, "mode", "m", "active", "Discovery mode: active (default), passive, or local (mDNS/NetBIOS only)")
+	rootCmd.Flags().BoolVar(&nmapFlag, "nmap", false, "Enable detailed Nmap scanning (Active mode only)")
+	rootCmd.Flags().StringVar(&nmapScriptsFlag, "nmap-scripts", "", "NSE script categories/names to run, e.g. default,vuln,safe (default: nmap's -sC default set)")
+	rootCmd.Flags().StringVar(&nmapTimingFlag, "nmap-timing", "", "Nmap timing template, e.g. T4 (default: nmap's own default)")
+	rootCmd.Flags().StringVar(&nmapArgsFlag, "nmap-args", "", "Extra raw arguments appended to the nmap invocation, space-separated")
+	rootCmd.Flags().StringVar(&templatesFlag, "templates", "", "Directory of extra Nuclei-style YAML vulnerability templates, on top of the built-in set")
+	rootCmd.Flags().StringVar(&severityFlag, "severity", "", "Minimum vulnerability severity to report: info, low, medium, high, or critical (default: info)")
+	rootCmd.Flags().StringVar(&sourcesFlag, "sources", "", "Comma-separated passive discovery sources to run (default: all available): amass,ct,shodan,censys,securitytrails,virustotal,otx,hackertarget,dns-brute")
+	rootCmd.Flags().StringVar(&excludeSourcesFlag, "exclude-sources", "", "Comma-separated passive discovery sources to skip")
+	rootCmd.Flags().StringVar(&outputFormatFlag, "output-format", "txt,csv", "Comma-separated report formats: txt,csv,json,jsonl,sarif")
+	rootCmd.Flags().StringVar(&scanModeFlag, "scan-mode", "masscan", "Fast port scan backend: masscan, syn (native raw-socket scanner), or connect (plain TCP connect, no raw sockets)")
+	rootCmd.Flags().IntVar(&rateFlag, "rate", 1000, "Packets per second for --scan-mode syn")
+	rootCmd.Flags().BoolVar(&bruteFlag, "brute", false, "Enable credential brute-forcing against identified services (Active mode only)")
+	rootCmd.Flags().StringVar(&usersFlag, "users", "", "File of usernames to try, one per line (required with --brute)")
+	rootCmd.Flags().StringVar(&passwordsFlag, "passwords", "", "File of passwords to try, one per line")
+	rootCmd.Flags().StringVar(&userpassFlag, "userpass", "", "File of additional user:pass credential pairs to try")
+	rootCmd.Flags().IntVar(&bruteTimeoutFlag, "brute-timeout", 300, "Overall time budget in seconds for brute-forcing all targets")
+	rootCmd.Flags().BoolVar(&allowPrivateFlag, "allow-private", false, "Allow brute-forcing RFC1918/loopback/link-local targets")
+	rootCmd.Flags().BoolVar(&haveAuthFlag, "i-have-authorization", false, "Confirm you have explicit authorization to brute-force these targets")
+	rootCmd.Flags().StringVar(&logLevelFlag, "log-level", "info", "Minimum log level: debug, info, warn, or error")
+	rootCmd.Flags().StringVar(&logFormatFlag, "log-format", "text", "Log output format: text (colored) or json (newline-delimited, for CI/SIEM)")
+	rootCmd.Flags().StringVar(&stateFlag, "state", "", "Path to the state file for checkpointing scan progress (default: <output dir>/state.db)")
+	rootCmd.Flags().StringVar(&resumeFlag, "resume", "", "Resume a previous scan from this state.db file, skipping already-completed work")
+	rootCmd.Flags().BoolVar(&resumeLastFlag, "resume-last", false, "Resume the most recently modified state.db found under the current directory")
+	rootCmd.Flags().StringVar(&serveFlag, "serve", "", "Address (e.g. :8080) to serve a live scan API, SSE event stream, and Prometheus /metrics on")
+
+	rootCmd.MarkFlagsMutuallyExclusive("target", "file")
+	rootCmd.MarkFlagsMutuallyExclusive("resume", "resume-last")
+	rootCmd.MarkFlagsMutuallyExclusive("resume", "state")
+	rootCmd.MarkFlagsMutuallyExclusive("resume-last", "state")
+
+	rootCmd.AddCommand(newStatusCmd())
+
+	if err := rootCmd.Execute(); err != nil {
+		red.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newStatusCmd builds the "pdive2 status <state.db>" subcommand, which
+// reports per-phase completion counts from a checkpointed state file
+// without running a scan.
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <state.db>",
+		Short: "Print phase completion counts from a scan's state file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := args[0]
+
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			store, err := state.Open(path)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			progress, err := store.Load()
+			if err != nil {
+				return err
+			}
+			if progress == nil {
+				fmt.Printf("%s has no checkpointed progress yet.\n", path)
+				return nil
+			}
+
+			doneCount := func(m map[string]bool) int {
+				n := 0
+				for _, done := range m {
+					if done {
+						n++
+					}
+				}
+				return n
+			}
+
+			fmt.Printf("Resume token:        %s\n", progress.ResumeToken)
+			fmt.Printf("Passive discovery:   done=%v hosts=%d\n", progress.PassiveDone, len(progress.PassiveHosts))
+			fmt.Printf("Host discovery:      done=%v up=%d down=%d\n", progress.HostDiscoveryDone, len(progress.HostsUp), len(progress.HostsDown))
+			fmt.Printf("Port scan:           %d host(s) completed\n", doneCount(progress.PortScanDone))
+			fmt.Printf("Nmap:                %d host(s) completed\n", doneCount(progress.NmapDone))
+			fmt.Printf("Service enumeration: %d host(s) completed\n", doneCount(progress.ServiceEnumDone))
+
+			return nil
+		},
+	}
+}
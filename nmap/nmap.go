@@ -0,0 +1,235 @@
+// Package nmap shells out to the system nmap binary for detailed
+// service/OS fingerprinting of the ports MasscanScan already found open,
+// streaming its `-oX -` XML output from stdout and parsing it into
+// typed Go structs rather than scraping nmap's human-readable output.
+package nmap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Service describes the service nmap fingerprinted on a port.
+type Service struct {
+	Name      string
+	Product   string
+	Version   string
+	ExtraInfo string
+}
+
+// Script is the output of one NSE script that ran against a port.
+type Script struct {
+	ID     string
+	Output string
+}
+
+// Port is a single scanned port and everything nmap learned about it.
+type Port struct {
+	Number   int
+	Protocol string
+	State    string
+	Service  Service
+	Scripts  []Script
+}
+
+// OSMatch is one candidate OS guess from nmap's -O fingerprinting,
+// ordered by nmap's own confidence (best match first).
+type OSMatch struct {
+	Name     string
+	Accuracy int
+}
+
+// Host is everything nmap reported for a single scanned host.
+type Host struct {
+	Address   string
+	Hostname  string
+	Ports     []Port
+	OSMatches []OSMatch
+}
+
+// Config holds the user-facing knobs exposed as --nmap-scripts,
+// --nmap-timing, and --nmap-args.
+type Config struct {
+	// Scripts is an NSE script category/name list, e.g.
+	// "default,vuln,safe", passed as --script=.... Empty runs nmap's
+	// default scripts (-sC) instead.
+	Scripts string
+	// Timing is a timing template such as "T4". Empty leaves nmap's own
+	// default in place.
+	Timing string
+	// ExtraArgs are appended to the nmap invocation verbatim, after
+	// every other flag, so they can override anything above.
+	ExtraArgs []string
+}
+
+// NmapScanner runs nmap against one host/port-set at a time, the same
+// granularity scanHostPorts and enumerateService already use.
+type NmapScanner struct {
+	cfg Config
+}
+
+// NewNmapScanner returns a scanner that will invoke nmap with cfg's
+// flags on every Scan call.
+func NewNmapScanner(cfg Config) *NmapScanner {
+	return &NmapScanner{cfg: cfg}
+}
+
+// Scan runs nmap -sV -O (plus scripts/timing/extra args from cfg)
+// against host, restricted to ports, and parses its XML output. ctx
+// bounds the whole invocation: cancelling it kills the nmap child
+// process via exec.CommandContext.
+func (s *NmapScanner) Scan(ctx context.Context, host string, ports []int) (Host, error) {
+	if len(ports) == 0 {
+		return Host{}, fmt.Errorf("nmap: no ports to scan on %s", host)
+	}
+	if _, err := exec.LookPath("nmap"); err != nil {
+		return Host{}, fmt.Errorf("nmap not found in PATH: %w", err)
+	}
+
+	args := []string{"-sV", "-O", "-oX", "-", "-p", joinPorts(ports)}
+	if s.cfg.Timing != "" {
+		args = append(args, "-"+s.cfg.Timing)
+	}
+	if s.cfg.Scripts != "" {
+		args = append(args, "--script="+s.cfg.Scripts)
+	} else {
+		args = append(args, "-sC")
+	}
+	args = append(args, s.cfg.ExtraArgs...)
+	args = append(args, host)
+
+	cmd := exec.CommandContext(ctx, "nmap", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return Host{}, fmt.Errorf("nmap: failed to open stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return Host{}, fmt.Errorf("nmap: failed to start: %w", err)
+	}
+
+	var run xmlRun
+	decodeErr := xml.NewDecoder(stdout).Decode(&run)
+	waitErr := cmd.Wait()
+	if waitErr != nil {
+		return Host{}, fmt.Errorf("nmap: %w", waitErr)
+	}
+	if decodeErr != nil {
+		return Host{}, fmt.Errorf("nmap: failed to parse XML output: %w", decodeErr)
+	}
+
+	if len(run.Hosts) == 0 {
+		return Host{Address: host}, nil
+	}
+	return convertHost(run.Hosts[0]), nil
+}
+
+func joinPorts(ports []int) string {
+	s := make([]string, len(ports))
+	for i, p := range ports {
+		s[i] = strconv.Itoa(p)
+	}
+	return strings.Join(s, ",")
+}
+
+func convertHost(h xmlHost) Host {
+	out := Host{Address: h.addr()}
+
+	if len(h.Hostnames.Hostname) > 0 {
+		out.Hostname = h.Hostnames.Hostname[0].Name
+	}
+
+	for _, p := range h.Ports.Port {
+		port := Port{
+			Number:   p.PortID,
+			Protocol: p.Protocol,
+			State:    p.State.State,
+			Service: Service{
+				Name:      p.Service.Name,
+				Product:   p.Service.Product,
+				Version:   p.Service.Version,
+				ExtraInfo: p.Service.ExtraInfo,
+			},
+		}
+		for _, sc := range p.Scripts {
+			port.Scripts = append(port.Scripts, Script{ID: sc.ID, Output: sc.Output})
+		}
+		out.Ports = append(out.Ports, port)
+	}
+
+	for _, m := range h.OS.OSMatch {
+		accuracy, _ := strconv.Atoi(m.Accuracy)
+		out.OSMatches = append(out.OSMatches, OSMatch{Name: m.Name, Accuracy: accuracy})
+	}
+
+	return out
+}
+
+// xmlRun/xmlHost/... mirror just the parts of nmap's -oX schema this
+// package uses.
+type xmlRun struct {
+	XMLName xml.Name  `xml:"nmaprun"`
+	Hosts   []xmlHost `xml:"host"`
+}
+
+type xmlHost struct {
+	Addresses []xmlAddress `xml:"address"`
+	Hostnames struct {
+		Hostname []xmlHostname `xml:"hostname"`
+	} `xml:"hostnames"`
+	Ports struct {
+		Port []xmlPort `xml:"port"`
+	} `xml:"ports"`
+	OS struct {
+		OSMatch []xmlOSMatch `xml:"osmatch"`
+	} `xml:"os"`
+}
+
+// addr returns the first IPv4/IPv6 address reported for the host.
+func (h xmlHost) addr() string {
+	for _, a := range h.Addresses {
+		if a.AddrType == "ipv4" || a.AddrType == "ipv6" {
+			return a.Addr
+		}
+	}
+	if len(h.Addresses) > 0 {
+		return h.Addresses[0].Addr
+	}
+	return ""
+}
+
+type xmlAddress struct {
+	Addr     string `xml:"addr,attr"`
+	AddrType string `xml:"addrtype,attr"`
+}
+
+type xmlHostname struct {
+	Name string `xml:"name,attr"`
+}
+
+type xmlPort struct {
+	Protocol string `xml:"protocol,attr"`
+	PortID   int    `xml:"portid,attr"`
+	State    struct {
+		State string `xml:"state,attr"`
+	} `xml:"state"`
+	Service struct {
+		Name      string `xml:"name,attr"`
+		Product   string `xml:"product,attr"`
+		Version   string `xml:"version,attr"`
+		ExtraInfo string `xml:"extrainfo,attr"`
+	} `xml:"service"`
+	Scripts []struct {
+		ID     string `xml:"id,attr"`
+		Output string `xml:"output,attr"`
+	} `xml:"script"`
+}
+
+type xmlOSMatch struct {
+	Name     string `xml:"name,attr"`
+	Accuracy string `xml:"accuracy,attr"`
+}
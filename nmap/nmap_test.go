@@ -0,0 +1,93 @@
+package nmap
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestConvertHost(t *testing.T) {
+	rawXML := `
+<nmaprun>
+  <host>
+    <address addr="10.0.0.5" addrtype="ipv4"/>
+    <hostnames>
+      <hostname name="target.local"/>
+    </hostnames>
+    <ports>
+      <port protocol="tcp" portid="22">
+        <state state="open"/>
+        <service name="ssh" product="OpenSSH" version="8.9" extrainfo="Ubuntu Linux"/>
+        <script id="ssh2-enum-algos" output="some algos"/>
+      </port>
+      <port protocol="tcp" portid="80">
+        <state state="closed"/>
+        <service name="http"/>
+      </port>
+    </ports>
+    <os>
+      <osmatch name="Linux 5.X" accuracy="95"/>
+      <osmatch name="Linux 4.X" accuracy="80"/>
+    </os>
+  </host>
+</nmaprun>`
+
+	var run xmlRun
+	if err := xml.Unmarshal([]byte(rawXML), &run); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+	if len(run.Hosts) != 1 {
+		t.Fatalf("len(run.Hosts) = %d, want 1", len(run.Hosts))
+	}
+
+	host := convertHost(run.Hosts[0])
+
+	if host.Address != "10.0.0.5" {
+		t.Errorf("Address = %q, want %q", host.Address, "10.0.0.5")
+	}
+	if host.Hostname != "target.local" {
+		t.Errorf("Hostname = %q, want %q", host.Hostname, "target.local")
+	}
+	if len(host.Ports) != 2 {
+		t.Fatalf("len(Ports) = %d, want 2", len(host.Ports))
+	}
+
+	sshPort := host.Ports[0]
+	if sshPort.Number != 22 || sshPort.State != "open" {
+		t.Errorf("Ports[0] = %+v, want Number=22 State=open", sshPort)
+	}
+	if sshPort.Service.Product != "OpenSSH" || sshPort.Service.Version != "8.9" {
+		t.Errorf("Ports[0].Service = %+v, want Product=OpenSSH Version=8.9", sshPort.Service)
+	}
+	if len(sshPort.Scripts) != 1 || sshPort.Scripts[0].ID != "ssh2-enum-algos" {
+		t.Errorf("Ports[0].Scripts = %+v, want one ssh2-enum-algos entry", sshPort.Scripts)
+	}
+
+	if len(host.OSMatches) != 2 {
+		t.Fatalf("len(OSMatches) = %d, want 2", len(host.OSMatches))
+	}
+	if host.OSMatches[0].Name != "Linux 5.X" || host.OSMatches[0].Accuracy != 95 {
+		t.Errorf("OSMatches[0] = %+v, want Name=Linux 5.X Accuracy=95", host.OSMatches[0])
+	}
+}
+
+func TestConvertHost_PrefersIPAddressOverOtherAddressTypes(t *testing.T) {
+	h := xmlHost{
+		Addresses: []xmlAddress{
+			{Addr: "AA:BB:CC:DD:EE:FF", AddrType: "mac"},
+			{Addr: "10.0.0.9", AddrType: "ipv4"},
+		},
+	}
+
+	if got := convertHost(h).Address; got != "10.0.0.9" {
+		t.Errorf("Address = %q, want %q", got, "10.0.0.9")
+	}
+}
+
+func TestJoinPorts(t *testing.T) {
+	if got := joinPorts([]int{22, 80, 443}); got != "22,80,443" {
+		t.Errorf("joinPorts = %q, want %q", got, "22,80,443")
+	}
+	if got := joinPorts(nil); got != "" {
+		t.Errorf("joinPorts(nil) = %q, want empty string", got)
+	}
+}
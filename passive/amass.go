@@ -0,0 +1,49 @@
+package passive
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// amassTimeout bounds a single amass invocation, since -passive mode can
+// otherwise run far longer than the other sources' API calls.
+const amassTimeout = 60 * time.Second
+
+// amassSource shells out to the amass binary in passive mode. Unlike the
+// API-backed sources it isn't rate-limited by a provider, but it is
+// bounded by amassTimeout so one slow run can't stall the others.
+type amassSource struct{}
+
+// NewAmassSource returns a Source backed by the amass CLI tool. Discover
+// is a no-op (not an error) when amass isn't installed.
+func NewAmassSource() Source {
+	return amassSource{}
+}
+
+func (amassSource) Name() string { return "amass" }
+
+func (amassSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	if _, err := exec.LookPath("amass"); err != nil {
+		return nil, nil
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, amassTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, "amass", "enum", "-d", domain, "-passive")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("amass failed: %w", err)
+	}
+
+	var hosts []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			hosts = append(hosts, line)
+		}
+	}
+	return hosts, nil
+}
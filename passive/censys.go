@@ -0,0 +1,75 @@
+package passive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// censysSource queries the Censys Search v2 certificates API, which
+// indexes observed TLS certificates and their SANs.
+type censysSource struct {
+	id, secret string
+	httpClient *http.Client
+}
+
+// NewCensysSource returns a Source backed by Censys. Empty credentials
+// make Discover a no-op.
+func NewCensysSource(id, secret string) Source {
+	return censysSource{id: id, secret: secret, httpClient: &http.Client{}}
+}
+
+func (censysSource) Name() string { return "censys" }
+
+type censysCertSearchRequest struct {
+	Query string `json:"q"`
+}
+
+type censysCertSearchResponse struct {
+	Result struct {
+		Hits []struct {
+			Names []string `json:"names"`
+		} `json:"hits"`
+	} `json:"result"`
+}
+
+func (s censysSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	if s.id == "" || s.secret == "" {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(censysCertSearchRequest{Query: "names: " + domain})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://search.censys.io/api/v2/certificates/search", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(s.id, s.secret)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("censys request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("censys returned %s", resp.Status)
+	}
+
+	var out censysCertSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("censys: failed to decode response: %w", err)
+	}
+
+	var hosts []string
+	for _, hit := range out.Result.Hits {
+		hosts = append(hosts, hit.Names...)
+	}
+	return hosts, nil
+}
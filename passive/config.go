@@ -0,0 +1,55 @@
+package passive
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config carries the API keys read from ~/.config/pdive2/providers.yaml,
+// one field per source that needs a key. A missing file or key is not an
+// error: the corresponding source just reports itself unconfigured.
+type Config struct {
+	Shodan         string `yaml:"shodan"`
+	CensysID       string `yaml:"censys_id"`
+	CensysSecret   string `yaml:"censys_secret"`
+	SecurityTrails string `yaml:"securitytrails"`
+	VirusTotal     string `yaml:"virustotal"`
+	AlienVaultOTX  string `yaml:"otx"`
+}
+
+// LoadConfig reads ~/.config/pdive2/providers.yaml if present, then
+// applies any PDIVE2_*_KEY environment variable overrides on top, for
+// CI/container use where dropping a config file on disk is inconvenient.
+func LoadConfig() Config {
+	var cfg Config
+
+	if home, err := os.UserHomeDir(); err == nil {
+		path := filepath.Join(home, ".config", "pdive2", "providers.yaml")
+		if data, err := os.ReadFile(path); err == nil {
+			_ = yaml.Unmarshal(data, &cfg)
+		}
+	}
+
+	if v := os.Getenv("PDIVE2_SHODAN_KEY"); v != "" {
+		cfg.Shodan = v
+	}
+	if v := os.Getenv("PDIVE2_CENSYS_ID"); v != "" {
+		cfg.CensysID = v
+	}
+	if v := os.Getenv("PDIVE2_CENSYS_SECRET"); v != "" {
+		cfg.CensysSecret = v
+	}
+	if v := os.Getenv("PDIVE2_SECURITYTRAILS_KEY"); v != "" {
+		cfg.SecurityTrails = v
+	}
+	if v := os.Getenv("PDIVE2_VIRUSTOTAL_KEY"); v != "" {
+		cfg.VirusTotal = v
+	}
+	if v := os.Getenv("PDIVE2_OTX_KEY"); v != "" {
+		cfg.AlienVaultOTX = v
+	}
+
+	return cfg
+}
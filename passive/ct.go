@@ -0,0 +1,63 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ctSource queries crt.sh's JSON API, which mirrors Certificate
+// Transparency log entries for a domain — no API key required.
+type ctSource struct {
+	httpClient *http.Client
+}
+
+// NewCTSource returns a Source backed by crt.sh.
+func NewCTSource() Source {
+	return ctSource{httpClient: &http.Client{}}
+}
+
+func (ctSource) Name() string { return "ct" }
+
+type ctEntry struct {
+	NameValue string `json:"name_value"`
+}
+
+func (s ctSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	url := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("crt.sh request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crt.sh returned %s", resp.Status)
+	}
+
+	var entries []ctEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("crt.sh: failed to decode response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, e := range entries {
+		for _, line := range strings.Split(e.NameValue, "\n") {
+			host := strings.TrimPrefix(strings.TrimSpace(line), "*.")
+			if host == "" || seen[host] {
+				continue
+			}
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
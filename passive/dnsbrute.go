@@ -0,0 +1,81 @@
+package passive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+//go:embed wordlists/subdomains.txt
+var wordlistFS embed.FS
+
+// dnsBruteSource resolves a bundled wordlist of common subdomain
+// prefixes against domain, concurrently, keeping only the ones that
+// actually resolve.
+type dnsBruteSource struct {
+	resolver    *net.Resolver
+	concurrency int
+}
+
+// NewDNSBruteSource returns a Source that brute-forces domain's DNS
+// using the bundled wordlist.
+func NewDNSBruteSource() Source {
+	return dnsBruteSource{resolver: net.DefaultResolver, concurrency: 20}
+}
+
+func (dnsBruteSource) Name() string { return "dns-brute" }
+
+func (s dnsBruteSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	data, err := wordlistFS.ReadFile("wordlists/subdomains.txt")
+	if err != nil {
+		return nil, fmt.Errorf("dns-brute: failed to load wordlist: %w", err)
+	}
+
+	var words []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		w := strings.TrimSpace(scanner.Text())
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var hosts []string
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for word := range jobs {
+				candidate := fmt.Sprintf("%s.%s", word, domain)
+				if _, err := s.resolver.LookupHost(ctx, candidate); err == nil {
+					mu.Lock()
+					hosts = append(hosts, candidate)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, word := range words {
+		select {
+		case jobs <- word:
+		case <-ctx.Done():
+			close(jobs)
+			wg.Wait()
+			return hosts, ctx.Err()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return hosts, nil
+}
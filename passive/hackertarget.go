@@ -0,0 +1,55 @@
+package passive
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// hackerTargetSource queries HackerTarget's free hostsearch API, which
+// returns "hostname,ip" lines with no key required (rate limited per
+// source IP).
+type hackerTargetSource struct {
+	httpClient *http.Client
+}
+
+// NewHackerTargetSource returns a Source backed by HackerTarget.
+func NewHackerTargetSource() Source {
+	return hackerTargetSource{httpClient: &http.Client{}}
+}
+
+func (hackerTargetSource) Name() string { return "hackertarget" }
+
+func (s hackerTargetSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	reqURL := fmt.Sprintf("https://api.hackertarget.com/hostsearch/?q=%s", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("hackertarget request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hackertarget returned %s", resp.Status)
+	}
+
+	var hosts []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "error") {
+			continue
+		}
+		host, _, found := strings.Cut(line, ",")
+		if found && host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, scanner.Err()
+}
@@ -0,0 +1,69 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// otxSource queries AlienVault OTX's passive DNS endpoint for hostnames
+// that have resolved to the domain's infrastructure.
+type otxSource struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewOTXSource returns a Source backed by AlienVault OTX. An empty
+// apiKey makes Discover a no-op (OTX's passive DNS endpoint requires an
+// account, even on the free tier).
+func NewOTXSource(apiKey string) Source {
+	return otxSource{apiKey: apiKey, httpClient: &http.Client{}}
+}
+
+func (otxSource) Name() string { return "otx" }
+
+type otxPassiveDNSResponse struct {
+	PassiveDNS []struct {
+		Hostname string `json:"hostname"`
+	} `json:"passive_dns"`
+}
+
+func (s otxSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	if s.apiKey == "" {
+		return nil, nil
+	}
+
+	reqURL := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-OTX-API-KEY", s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("otx request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("otx returned %s", resp.Status)
+	}
+
+	var out otxPassiveDNSResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("otx: failed to decode response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, rec := range out.PassiveDNS {
+		if rec.Hostname == "" || seen[rec.Hostname] {
+			continue
+		}
+		seen[rec.Hostname] = true
+		hosts = append(hosts, rec.Hostname)
+	}
+	return hosts, nil
+}
@@ -0,0 +1,126 @@
+package passive
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hero2zero/pdive2/log"
+)
+
+// DefaultSourceTimeout bounds how long any single source gets to answer
+// for one domain, so one slow/unreachable API can't stall the others.
+const DefaultSourceTimeout = 20 * time.Second
+
+// Run queries every source for domain concurrently, each under its own
+// DefaultSourceTimeout, and returns the deduplicated union of hosts with
+// per-host attribution. A source that errors or times out is logged and
+// simply contributes no hosts.
+func Run(ctx context.Context, domain string, sources []Source) []Result {
+	type hit struct {
+		host   string
+		source string
+	}
+
+	hits := make(chan hit)
+	var wg sync.WaitGroup
+	for _, src := range sources {
+		wg.Add(1)
+		go func(src Source) {
+			defer wg.Done()
+
+			srcCtx, cancel := context.WithTimeout(ctx, DefaultSourceTimeout)
+			defer cancel()
+
+			hosts, err := src.Discover(srcCtx, domain)
+			if err != nil {
+				log.Warnf("passive", "%s: %v", src.Name(), err)
+				return
+			}
+			for _, h := range hosts {
+				hits <- hit{host: normalizeHost(h), source: src.Name()}
+			}
+		}(src)
+	}
+
+	go func() {
+		wg.Wait()
+		close(hits)
+	}()
+
+	bySources := make(map[string]map[string]bool)
+	for h := range hits {
+		if h.host == "" {
+			continue
+		}
+		if bySources[h.host] == nil {
+			bySources[h.host] = make(map[string]bool)
+		}
+		bySources[h.host][h.source] = true
+	}
+
+	results := make([]Result, 0, len(bySources))
+	for host, srcSet := range bySources {
+		var srcs []string
+		for s := range srcSet {
+			srcs = append(srcs, s)
+		}
+		sort.Strings(srcs)
+		results = append(results, Result{Host: host, Sources: srcs})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Host < results[j].Host })
+
+	return results
+}
+
+// normalizeHost lowercases and trims a discovered hostname, and strips a
+// trailing dot (several CT/DNS APIs return FQDNs with one).
+func normalizeHost(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	return strings.TrimSuffix(h, ".")
+}
+
+// Select resolves the --sources/--exclude-sources flags against the full
+// set of available sources: an empty include list means "all of them".
+// Names not matching any available source are returned in unknown, so
+// the caller can warn about typos instead of silently ignoring them.
+func Select(all []Source, include, exclude []string) (selected []Source, unknown []string) {
+	byName := make(map[string]Source, len(all))
+	for _, s := range all {
+		byName[s.Name()] = s
+	}
+
+	seen := func(names []string) []string {
+		var bad []string
+		for _, n := range names {
+			if _, ok := byName[n]; !ok {
+				bad = append(bad, n)
+			}
+		}
+		return bad
+	}
+	unknown = append(seen(include), seen(exclude)...)
+
+	excluded := make(map[string]bool, len(exclude))
+	for _, n := range exclude {
+		excluded[n] = true
+	}
+
+	if len(include) == 0 {
+		for _, s := range all {
+			if !excluded[s.Name()] {
+				selected = append(selected, s)
+			}
+		}
+		return selected, unknown
+	}
+
+	for _, n := range include {
+		if s, ok := byName[n]; ok && !excluded[n] {
+			selected = append(selected, s)
+		}
+	}
+	return selected, unknown
+}
@@ -0,0 +1,61 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// securityTrailsSource queries SecurityTrails' subdomain listing
+// endpoint.
+type securityTrailsSource struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewSecurityTrailsSource returns a Source backed by SecurityTrails. An
+// empty apiKey makes Discover a no-op.
+func NewSecurityTrailsSource(apiKey string) Source {
+	return securityTrailsSource{apiKey: apiKey, httpClient: &http.Client{}}
+}
+
+func (securityTrailsSource) Name() string { return "securitytrails" }
+
+type securityTrailsResponse struct {
+	Subdomains []string `json:"subdomains"`
+}
+
+func (s securityTrailsSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	if s.apiKey == "" {
+		return nil, nil
+	}
+
+	reqURL := fmt.Sprintf("https://api.securitytrails.com/v1/domain/%s/subdomains", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("APIKEY", s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("securitytrails request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("securitytrails returned %s", resp.Status)
+	}
+
+	var out securityTrailsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("securitytrails: failed to decode response: %w", err)
+	}
+
+	hosts := make([]string, 0, len(out.Subdomains))
+	for _, sub := range out.Subdomains {
+		hosts = append(hosts, fmt.Sprintf("%s.%s", sub, domain))
+	}
+	return hosts, nil
+}
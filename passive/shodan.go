@@ -0,0 +1,61 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// shodanSource queries Shodan's DNS domain endpoint, which returns every
+// subdomain Shodan has observed in scan data for a domain.
+type shodanSource struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewShodanSource returns a Source backed by the Shodan API. An empty
+// apiKey makes Discover a no-op, since Shodan requires one.
+func NewShodanSource(apiKey string) Source {
+	return shodanSource{apiKey: apiKey, httpClient: &http.Client{}}
+}
+
+func (shodanSource) Name() string { return "shodan" }
+
+type shodanDNSResponse struct {
+	Subdomains []string `json:"subdomains"`
+}
+
+func (s shodanSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	if s.apiKey == "" {
+		return nil, nil
+	}
+
+	reqURL := fmt.Sprintf("https://api.shodan.io/dns/domain/%s?key=%s", url.PathEscape(domain), url.QueryEscape(s.apiKey))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("shodan request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("shodan returned %s", resp.Status)
+	}
+
+	var out shodanDNSResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("shodan: failed to decode response: %w", err)
+	}
+
+	hosts := make([]string, 0, len(out.Subdomains))
+	for _, sub := range out.Subdomains {
+		hosts = append(hosts, fmt.Sprintf("%s.%s", sub, domain))
+	}
+	return hosts, nil
+}
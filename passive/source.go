@@ -0,0 +1,30 @@
+// Package passive implements pluggable passive subdomain/host discovery.
+// Each Source queries one external data set (a CT log aggregator, a
+// paid recon API, a local wordlist, ...) for hostnames under a domain
+// without ever touching the target directly. Run fans a domain out to a
+// set of sources concurrently, deduplicates their results, and records
+// which source(s) turned up each host.
+package passive
+
+import "context"
+
+// Source discovers hostnames for domain without sending any traffic to
+// the domain's own infrastructure.
+type Source interface {
+	// Name identifies this source for --sources/--exclude-sources and for
+	// attributing discovered hosts, e.g. "ct", "shodan", "dns-brute".
+	Name() string
+	// Discover returns the hostnames this source knows about for domain.
+	// A source with no results (including one that isn't configured,
+	// e.g. missing API key) returns an empty slice and a nil error;
+	// Discover only returns an error for something worth logging, like a
+	// request that failed outright.
+	Discover(ctx context.Context, domain string) ([]string, error)
+}
+
+// Result is one host discovered during a Run, attributed to every source
+// that reported it.
+type Result struct {
+	Host    string
+	Sources []string
+}
@@ -0,0 +1,63 @@
+package passive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// virusTotalSource queries VirusTotal's domain relations API for
+// observed subdomains.
+type virusTotalSource struct {
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewVirusTotalSource returns a Source backed by VirusTotal. An empty
+// apiKey makes Discover a no-op.
+func NewVirusTotalSource(apiKey string) Source {
+	return virusTotalSource{apiKey: apiKey, httpClient: &http.Client{}}
+}
+
+func (virusTotalSource) Name() string { return "virustotal" }
+
+type virusTotalResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+func (s virusTotalSource) Discover(ctx context.Context, domain string) ([]string, error) {
+	if s.apiKey == "" {
+		return nil, nil
+	}
+
+	reqURL := fmt.Sprintf("https://www.virustotal.com/api/v3/domains/%s/subdomains?limit=40", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-apikey", s.apiKey)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("virustotal request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("virustotal returned %s", resp.Status)
+	}
+
+	var out virusTotalResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("virustotal: failed to decode response: %w", err)
+	}
+
+	hosts := make([]string, 0, len(out.Data))
+	for _, d := range out.Data {
+		hosts = append(hosts, d.ID)
+	}
+	return hosts, nil
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type csvWriter struct{}
+
+func (csvWriter) Ext() string { return "csv" }
+
+func (csvWriter) Write(doc Document, w *os.File) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"Host", "Port", "Protocol", "State", "Service", "Findings", "Scan_Time"}); err != nil {
+		return err
+	}
+
+	scanTime := doc.ScanInfo.StartTime.Format("2006-01-02 15:04:05")
+
+	for _, host := range doc.Hosts {
+		if len(host.Ports) == 0 {
+			if err := writer.Write([]string{host.Host, "", "", "host_up", "no_open_ports", strings.Join(host.Sources, "; "), scanTime}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, port := range host.Ports {
+			service := port.Service
+			if service == "" {
+				service = "unknown"
+			}
+			record := []string{
+				host.Host,
+				strconv.Itoa(port.Port),
+				"tcp",
+				port.State,
+				service,
+				findingsSummary(port.Findings),
+				scanTime,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		for _, c := range host.Credentials {
+			record := []string{
+				host.Host,
+				strconv.Itoa(c.Port),
+				"tcp",
+				"credential",
+				c.Service,
+				fmt.Sprintf("%s / %s", c.Username, c.Password),
+				scanTime,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	vulns := make([]Vulnerability, len(doc.Vulnerabilities))
+	copy(vulns, doc.Vulnerabilities)
+	sortBySeverity(vulns)
+	for _, v := range vulns {
+		record := []string{
+			v.Host,
+			strconv.Itoa(v.Port),
+			"tcp",
+			fmt.Sprintf("vulnerability:%s", v.Severity),
+			v.ID,
+			vulnerabilitySummary(v),
+			scanTime,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return writer.Error()
+}
+
+// vulnerabilitySummary renders a Vulnerability's match location and any
+// extracted values as a single field so it fits CSV's flat row shape.
+func vulnerabilitySummary(v Vulnerability) string {
+	if len(v.Extracted) == 0 {
+		return v.MatchedAt
+	}
+	return fmt.Sprintf("%s; extracted: %s", v.MatchedAt, strings.Join(v.Extracted, ", "))
+}
+
+// findingsSummary renders a port's findings as a single semicolon-separated
+// field so they fit CSV's flat row shape.
+func findingsSummary(findings []Finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, f := range findings {
+		parts = append(parts, fmt.Sprintf("[%s] %s", f.Severity, f.Name))
+	}
+	return strings.Join(parts, "; ")
+}
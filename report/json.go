@@ -0,0 +1,32 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+)
+
+type jsonWriter struct{}
+
+func (jsonWriter) Ext() string { return "json" }
+
+func (jsonWriter) Write(doc Document, w *os.File) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// jsonlWriter emits one JSON object per host, newline-delimited, suitable
+// for streaming into log/pipeline tooling.
+type jsonlWriter struct{}
+
+func (jsonlWriter) Ext() string { return "jsonl" }
+
+func (jsonlWriter) Write(doc Document, w *os.File) error {
+	enc := json.NewEncoder(w)
+	for _, host := range doc.Hosts {
+		if err := enc.Encode(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Package report builds and writes PDive2 scan reports in multiple formats
+// (text, csv, json, jsonl, sarif) from a format-agnostic snapshot of the
+// scan results.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Port describes a single scanned port.
+type Port struct {
+	Port     int       `json:"port"`
+	State    string    `json:"state"`
+	Service  string    `json:"service"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Finding is a security-relevant observation made while fingerprinting the
+// service on a port, e.g. an outdated protocol dialect or a CVE signature.
+type Finding struct {
+	Name     string `json:"name"`
+	Severity string `json:"severity"`
+	Evidence string `json:"evidence"`
+	CVE      string `json:"cve,omitempty"`
+}
+
+// Host describes a single discovered host and its ports.
+type Host struct {
+	Host        string       `json:"host"`
+	Status      string       `json:"status"`
+	Ports       []Port       `json:"ports"`
+	Credentials []Credential `json:"credentials,omitempty"`
+	OSMatches   []string     `json:"os_matches,omitempty"`
+	// Sources names which passive-discovery source(s) reported this
+	// host, populated for passively discovered hosts only.
+	Sources []string `json:"sources,omitempty"`
+}
+
+// Credential is a valid username/password pair recovered by the
+// bruteforce package against one of a host's services.
+type Credential struct {
+	Service  string `json:"service"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ScanInfo carries metadata about the scan that produced a Document.
+type ScanInfo struct {
+	Targets       []string  `json:"targets"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Scanner       string    `json:"scanner"`
+	DiscoveryMode string    `json:"discovery_mode"`
+	// ResumeToken identifies the (possibly multi-run) scan this report
+	// belongs to. Reports from a --resume run share the token of the run
+	// they resumed, so results from before and after an interruption
+	// stitch together under the same identifier.
+	ResumeToken string `json:"resume_token,omitempty"`
+}
+
+// Summary carries the aggregate counts shown at the top of every report.
+type Summary struct {
+	TotalHosts           int `json:"total_hosts"`
+	TotalPorts           int `json:"total_open_ports"`
+	UnresponsiveHosts    int `json:"unresponsive_hosts"`
+	TotalVulnerabilities int `json:"total_vulnerabilities,omitempty"`
+}
+
+// Vulnerability is a single confirmed match from the vulnerability
+// scanning phase (Nuclei-style YAML templates run against identified
+// services), independent of any one port's Findings.
+type Vulnerability struct {
+	ID        string   `json:"id"`
+	Severity  string   `json:"severity"`
+	Host      string   `json:"host"`
+	Port      int      `json:"port"`
+	MatchedAt string   `json:"matched_at"`
+	Extracted []string `json:"extracted,omitempty"`
+}
+
+// Document is the format-agnostic snapshot every Writer renders from. It is
+// a plain DTO so the report package has no dependency on PDive2's internal,
+// mutex-guarded result types.
+type Document struct {
+	ScanInfo        ScanInfo        `json:"scan_info"`
+	Hosts           []Host          `json:"hosts"`
+	Summary         Summary         `json:"summary"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// Writer renders a Document to w in a specific format.
+type Writer interface {
+	// Write renders doc to w.
+	Write(doc Document, w *os.File) error
+	// Ext returns the filename extension (without leading dot) this writer
+	// produces, e.g. "json" or "jsonl".
+	Ext() string
+}
+
+// Formats maps a user-facing format name (as accepted by --output-format)
+// to its Writer implementation.
+var Formats = map[string]Writer{
+	"txt":   textWriter{},
+	"csv":   csvWriter{},
+	"json":  jsonWriter{},
+	"jsonl": jsonlWriter{},
+	"sarif": sarifWriter{},
+}
+
+// ValidFormat reports whether name is a known output format.
+func ValidFormat(name string) bool {
+	_, ok := Formats[name]
+	return ok
+}
+
+// severityRank orders severities from most to least urgent, for grouping
+// a Findings/Vulnerabilities section the same way across writers.
+var severityRank = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"info":     4,
+}
+
+// sortBySeverity sorts vulns most-severe first, breaking ties by host.
+func sortBySeverity(vulns []Vulnerability) {
+	sort.SliceStable(vulns, func(i, j int) bool {
+		ri, rj := severityRank[vulns[i].Severity], severityRank[vulns[j].Severity]
+		if ri != rj {
+			return ri < rj
+		}
+		return vulns[i].Host < vulns[j].Host
+	})
+}
+
+// WriteAll renders doc using each of the given formats, writing one file
+// per format into dir named "<baseName>_<timestamp>.<ext>". It returns the
+// paths written, in the same order as formats.
+func WriteAll(doc Document, dir, baseName string, formats []string, timestamp string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var paths []string
+	for _, format := range formats {
+		writer, ok := Formats[format]
+		if !ok {
+			return paths, fmt.Errorf("unknown output format: %s", format)
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%s_%s.%s", baseName, timestamp, writer.Ext()))
+		f, err := os.Create(path)
+		if err != nil {
+			return paths, fmt.Errorf("failed to create %s: %w", path, err)
+		}
+
+		err = writer.Write(doc, f)
+		closeErr := f.Close()
+		if err != nil {
+			return paths, fmt.Errorf("failed to write %s report: %w", format, err)
+		}
+		if closeErr != nil {
+			return paths, closeErr
+		}
+
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
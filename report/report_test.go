@@ -0,0 +1,124 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func sampleDocument() Document {
+	return Document{
+		ScanInfo: ScanInfo{
+			Targets:       []string{"10.0.0.5"},
+			StartTime:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			EndTime:       time.Date(2026, 1, 1, 0, 5, 0, 0, time.UTC),
+			Scanner:       "pdive2-test",
+			DiscoveryMode: "active",
+		},
+		Hosts: []Host{
+			{
+				Host:   "10.0.0.5",
+				Status: "up",
+				Ports: []Port{
+					{
+						Port:    22,
+						State:   "open",
+						Service: "ssh",
+						Findings: []Finding{
+							{Name: "weak-cipher", Severity: "medium", Evidence: "offers CBC ciphers"},
+						},
+					},
+				},
+			},
+			{
+				Host:   "10.0.0.6",
+				Status: "up",
+				Ports:  nil,
+			},
+		},
+		Summary: Summary{TotalHosts: 2, TotalPorts: 1},
+	}
+}
+
+func writeToTemp(t *testing.T, w Writer, doc Document) []byte {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "report-*."+w.Ext())
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	defer f.Close()
+
+	if err := w.Write(doc, f); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	return data
+}
+
+func TestJSONWriter_RoundTrip(t *testing.T) {
+	doc := sampleDocument()
+	data := writeToTemp(t, jsonWriter{}, doc)
+
+	var got Document
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Summary.TotalHosts != doc.Summary.TotalHosts {
+		t.Errorf("TotalHosts = %d, want %d", got.Summary.TotalHosts, doc.Summary.TotalHosts)
+	}
+	if len(got.Hosts) != len(doc.Hosts) {
+		t.Fatalf("len(Hosts) = %d, want %d", len(got.Hosts), len(doc.Hosts))
+	}
+	if got.Hosts[0].Ports[0].Findings[0].Name != "weak-cipher" {
+		t.Errorf("Hosts[0].Ports[0].Findings[0].Name = %q, want %q", got.Hosts[0].Ports[0].Findings[0].Name, "weak-cipher")
+	}
+}
+
+func TestJSONLWriter_OneObjectPerHost(t *testing.T) {
+	doc := sampleDocument()
+	data := writeToTemp(t, jsonlWriter{}, doc)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var hosts []Host
+	for {
+		var h Host
+		if err := dec.Decode(&h); err != nil {
+			break
+		}
+		hosts = append(hosts, h)
+	}
+
+	if len(hosts) != len(doc.Hosts) {
+		t.Fatalf("decoded %d objects, want %d (one per host)", len(hosts), len(doc.Hosts))
+	}
+	for i, h := range hosts {
+		if h.Host != doc.Hosts[i].Host {
+			t.Errorf("hosts[%d].Host = %q, want %q", i, h.Host, doc.Hosts[i].Host)
+		}
+	}
+}
+
+func TestSortBySeverity(t *testing.T) {
+	vulns := []Vulnerability{
+		{ID: "low-one", Severity: "low", Host: "b"},
+		{ID: "crit-one", Severity: "critical", Host: "a"},
+		{ID: "crit-two", Severity: "critical", Host: "b"},
+	}
+
+	sortBySeverity(vulns)
+
+	want := []string{"crit-one", "crit-two", "low-one"}
+	for i, id := range want {
+		if vulns[i].ID != id {
+			t.Errorf("vulns[%d].ID = %q, want %q", i, vulns[i].ID, id)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Minimal SARIF 2.1.0 object model — just enough of the spec to describe
+// open-port findings as locatable results.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	Address sarifAddress `json:"address"`
+}
+
+type sarifAddress struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	AbsoluteAddress    int    `json:"absoluteAddress"`
+}
+
+type sarifWriter struct{}
+
+func (sarifWriter) Ext() string { return "sarif" }
+
+func (sarifWriter) Write(doc Document, w *os.File) error {
+	ruleSeen := make(map[string]bool)
+	var rules []sarifRule
+	var results []sarifResult
+
+	for _, host := range doc.Hosts {
+		for _, port := range host.Ports {
+			service := port.Service
+			if service == "" {
+				service = "unknown"
+			}
+			ruleID := fmt.Sprintf("open-port/%s", service)
+
+			if !ruleSeen[ruleID] {
+				ruleSeen[ruleID] = true
+				rules = append(rules, sarifRule{
+					ID:               ruleID,
+					Name:             fmt.Sprintf("Open%sPort", service),
+					ShortDescription: sarifMessage{Text: fmt.Sprintf("An open port running %s was detected.", service)},
+				})
+			}
+
+			results = append(results, sarifResult{
+				RuleID: ruleID,
+				Level:  "note",
+				Message: sarifMessage{
+					Text: fmt.Sprintf("%s:%d is open, identified as %s", host.Host, port.Port, service),
+				},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							Address: sarifAddress{
+								FullyQualifiedName: fmt.Sprintf("%s:%d", host.Host, port.Port),
+								AbsoluteAddress:    port.Port,
+							},
+						},
+					},
+				},
+			})
+
+			for _, finding := range port.Findings {
+				findingRuleID := fmt.Sprintf("finding/%s", sarifSlug(finding.Name))
+
+				if !ruleSeen[findingRuleID] {
+					ruleSeen[findingRuleID] = true
+					rules = append(rules, sarifRule{
+						ID:               findingRuleID,
+						Name:             sarifSlug(finding.Name),
+						ShortDescription: sarifMessage{Text: finding.Name},
+					})
+				}
+
+				results = append(results, sarifResult{
+					RuleID: findingRuleID,
+					Level:  sarifLevel(finding.Severity),
+					Message: sarifMessage{
+						Text: fmt.Sprintf("%s:%d - %s", host.Host, port.Port, finding.Evidence),
+					},
+					Locations: []sarifLocation{
+						{
+							PhysicalLocation: sarifPhysicalLocation{
+								Address: sarifAddress{
+									FullyQualifiedName: fmt.Sprintf("%s:%d", host.Host, port.Port),
+									AbsoluteAddress:    port.Port,
+								},
+							},
+						},
+					},
+				})
+			}
+		}
+
+		for _, cred := range host.Credentials {
+			const ruleID = "credential/recovered"
+			if !ruleSeen[ruleID] {
+				ruleSeen[ruleID] = true
+				rules = append(rules, sarifRule{
+					ID:               ruleID,
+					Name:             "RecoveredCredential",
+					ShortDescription: sarifMessage{Text: "A valid credential was recovered via brute-force."},
+				})
+			}
+
+			results = append(results, sarifResult{
+				RuleID: ruleID,
+				Level:  "error",
+				Message: sarifMessage{
+					Text: fmt.Sprintf("%s:%d (%s) - valid credential %s / %s", host.Host, cred.Port, cred.Service, cred.Username, cred.Password),
+				},
+				Locations: []sarifLocation{
+					{
+						PhysicalLocation: sarifPhysicalLocation{
+							Address: sarifAddress{
+								FullyQualifiedName: fmt.Sprintf("%s:%d", host.Host, cred.Port),
+								AbsoluteAddress:    cred.Port,
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	for _, vuln := range doc.Vulnerabilities {
+		ruleID := fmt.Sprintf("vuln/%s", sarifSlug(vuln.ID))
+
+		if !ruleSeen[ruleID] {
+			ruleSeen[ruleID] = true
+			rules = append(rules, sarifRule{
+				ID:               ruleID,
+				Name:             sarifSlug(vuln.ID),
+				ShortDescription: sarifMessage{Text: vuln.ID},
+			})
+		}
+
+		results = append(results, sarifResult{
+			RuleID: ruleID,
+			Level:  sarifLevel(vuln.Severity),
+			Message: sarifMessage{
+				Text: fmt.Sprintf("%s:%d - %s matched at %s", vuln.Host, vuln.Port, vuln.ID, vuln.MatchedAt),
+			},
+			Locations: []sarifLocation{
+				{
+					PhysicalLocation: sarifPhysicalLocation{
+						Address: sarifAddress{
+							FullyQualifiedName: fmt.Sprintf("%s:%d", vuln.Host, vuln.Port),
+							AbsoluteAddress:    vuln.Port,
+						},
+					},
+				},
+			},
+		})
+	}
+
+	doc0 := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "pdive2",
+						InformationURI: "https://github.com/hero2zero/pdive2",
+						Version:        doc.ScanInfo.Scanner,
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc0)
+}
+
+// sarifLevel maps a Finding's severity to SARIF's result.level enum.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium", "low":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSlug turns a finding name into a rule ID/name safe identifier.
+func sarifSlug(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
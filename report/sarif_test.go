@@ -0,0 +1,84 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestSARIFWriter_MapsVulnerabilities(t *testing.T) {
+	doc := Document{
+		Vulnerabilities: []Vulnerability{
+			{ID: "CVE-2024-0001", Severity: "critical", Host: "10.0.0.5", Port: 443, MatchedAt: "/login"},
+		},
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "report-*.sarif")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	defer f.Close()
+
+	if err := (sarifWriter{}).Write(doc, f); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	var got sarifLog
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if len(got.Runs) != 1 {
+		t.Fatalf("len(Runs) = %d, want 1", len(got.Runs))
+	}
+	run := got.Runs[0]
+
+	wantRuleID := "vuln/CVE-2024-0001"
+	found := false
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ID == wantRuleID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("rules = %+v, want one with ID %q", run.Tool.Driver.Rules, wantRuleID)
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("len(Results) = %d, want 1", len(run.Results))
+	}
+	result := run.Results[0]
+	if result.RuleID != wantRuleID {
+		t.Errorf("Results[0].RuleID = %q, want %q", result.RuleID, wantRuleID)
+	}
+	if result.Level != "error" {
+		t.Errorf("Results[0].Level = %q, want %q (critical severity)", result.Level, "error")
+	}
+	if len(result.Locations) != 1 || result.Locations[0].PhysicalLocation.Address.AbsoluteAddress != 443 {
+		t.Errorf("Results[0].Locations = %+v, want absoluteAddress 443", result.Locations)
+	}
+}
+
+func TestSARIFLevel(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{"critical", "error"},
+		{"high", "error"},
+		{"medium", "warning"},
+		{"low", "warning"},
+		{"info", "note"},
+		{"unknown", "note"},
+	}
+	for _, tt := range tests {
+		if got := sarifLevel(tt.severity); got != tt.want {
+			t.Errorf("sarifLevel(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+type textWriter struct{}
+
+func (textWriter) Ext() string { return "txt" }
+
+func (textWriter) Write(doc Document, w *os.File) error {
+	fmt.Fprintln(w, "PDIVE2 SCAN REPORT")
+	fmt.Fprintln(w, strings.Repeat("=", 60))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SCAN SUMMARY")
+	fmt.Fprintln(w, strings.Repeat("-", 20))
+	fmt.Fprintln(w, "Targets:")
+	for _, target := range doc.ScanInfo.Targets {
+		fmt.Fprintf(w, "  %s\n", target)
+	}
+	fmt.Fprintf(w, "\nScan Start Time: %s\n", doc.ScanInfo.StartTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "Scan End Time: %s\n", doc.ScanInfo.EndTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "Scanner Version: %s\n", doc.ScanInfo.Scanner)
+	fmt.Fprintf(w, "Discovery Mode: %s\n", strings.ToUpper(doc.ScanInfo.DiscoveryMode))
+	fmt.Fprintf(w, "Total Hosts: %d\n", doc.Summary.TotalHosts)
+	fmt.Fprintf(w, "Total Open Ports: %d\n", doc.Summary.TotalPorts)
+	fmt.Fprintf(w, "Unresponsive Hosts: %d\n\n", doc.Summary.UnresponsiveHosts)
+
+	fmt.Fprintln(w, "DETAILED RESULTS")
+	fmt.Fprintln(w, strings.Repeat("-", 20))
+
+	if len(doc.Hosts) == 0 {
+		fmt.Fprintln(w, "No hosts discovered")
+		return nil
+	}
+
+	hosts := make([]Host, len(doc.Hosts))
+	copy(hosts, doc.Hosts)
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Host < hosts[j].Host })
+
+	for _, host := range hosts {
+		fmt.Fprintf(w, "\nHost: %s\n", host.Host)
+		fmt.Fprintln(w, strings.Repeat("=", len(host.Host)+6))
+		if len(host.Sources) > 0 {
+			fmt.Fprintf(w, "Discovered by: %s\n", strings.Join(host.Sources, ", "))
+		}
+		if len(host.Ports) > 0 {
+			fmt.Fprintln(w, "Open Ports:")
+			for _, port := range host.Ports {
+				service := port.Service
+				if service == "" {
+					service = "unknown"
+				}
+				fmt.Fprintf(w, "  %5d/tcp  %s\n", port.Port, service)
+				for _, f := range port.Findings {
+					fmt.Fprintf(w, "             [%s] %s: %s\n", strings.ToUpper(f.Severity), f.Name, f.Evidence)
+				}
+			}
+		} else {
+			fmt.Fprintln(w, "  No open ports detected")
+		}
+
+		if len(host.Credentials) > 0 {
+			fmt.Fprintln(w, "Recovered Credentials:")
+			for _, c := range host.Credentials {
+				fmt.Fprintf(w, "  %s:%d  %s / %s\n", c.Service, c.Port, c.Username, c.Password)
+			}
+		}
+	}
+
+	if len(doc.Vulnerabilities) > 0 {
+		fmt.Fprintln(w, "\nFINDINGS (by severity)")
+		fmt.Fprintln(w, strings.Repeat("-", 20))
+
+		vulns := make([]Vulnerability, len(doc.Vulnerabilities))
+		copy(vulns, doc.Vulnerabilities)
+		sortBySeverity(vulns)
+
+		for _, v := range vulns {
+			fmt.Fprintf(w, "  [%s] %s  %s:%d  (%s)\n", strings.ToUpper(v.Severity), v.ID, v.Host, v.Port, v.MatchedAt)
+			for _, ex := range v.Extracted {
+				fmt.Fprintf(w, "             extracted: %s\n", ex)
+			}
+		}
+	}
+
+	return nil
+}
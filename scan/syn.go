@@ -0,0 +1,416 @@
+// Package scan implements a native, masscan-free TCP SYN scanner. It
+// crafts and parses TCP segments with gopacket/layers and sends/receives
+// them over raw IPv4/IPv6 sockets, so a single binary can reach
+// masscan-like packet rates without shelling out to anything.
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// PortResult is the outcome of probing a single port.
+type PortResult struct {
+	Port  int
+	State string // "open" or "closed"
+}
+
+// ErrRawSocketUnavailable is returned by NewSYNScanner when the process
+// can't open a raw socket (e.g. missing CAP_NET_RAW), so callers can fall
+// back to a connect-based scan.
+var ErrRawSocketUnavailable = errors.New("scan: raw socket unavailable, need CAP_NET_RAW/root")
+
+// maxRetransmits bounds how many extra rounds the scanner will resend
+// probes that got no reply at all (as opposed to an explicit RST), to
+// recover from lost SYNs without retrying forever.
+const maxRetransmits = 2
+
+// SYNScanner sends stateless SYN probes and matches replies without
+// holding per-target state: the target's source port and sequence number
+// are derived from the host:port being probed, so any SYN-ACK/RST that
+// arrives can be validated and attributed on its own. It scans both IPv4
+// and IPv6 targets, each over its own raw socket.
+type SYNScanner struct {
+	conn4 *net.IPConn
+	conn6 *net.IPConn
+	rate  int // packets per second, shared between both address families
+}
+
+// NewSYNScanner opens the raw sockets used for both sending SYNs and
+// receiving replies. rate is the sender's token-bucket budget in packets
+// per second. IPv6 is best-effort: if the kernel won't give us a raw ip6
+// socket (some sandboxes/containers disable it even with CAP_NET_RAW),
+// the scanner still works, just IPv4-only.
+func NewSYNScanner(rate int) (*SYNScanner, error) {
+	conn4, err := net.ListenIP("ip4:tcp", &net.IPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, ErrRawSocketUnavailable
+	}
+	conn6, err := net.ListenIP("ip6:tcp", &net.IPAddr{IP: net.IPv6unspecified})
+	if err != nil {
+		conn6 = nil
+	}
+	if rate <= 0 {
+		rate = 1000
+	}
+	return &SYNScanner{conn4: conn4, conn6: conn6, rate: rate}, nil
+}
+
+// Close releases the raw sockets.
+func (s *SYNScanner) Close() error {
+	err := s.conn4.Close()
+	if s.conn6 != nil {
+		if err6 := s.conn6.Close(); err == nil {
+			err = err6
+		}
+	}
+	return err
+}
+
+// Scan probes every host:port pair and returns the open/closed ports per
+// host. dwell bounds how long the receiver keeps listening for
+// straggling replies after the last SYN of a round has been sent. Any
+// host:port that gets no reply at all (as opposed to an explicit RST) is
+// retransmitted up to maxRetransmits times before being given up on, to
+// tolerate ordinary packet loss rather than reporting it as closed.
+// Cancelling ctx stops the scan before its next round rather than only
+// being checked once up front, so a Ctrl+C during a long scan is acted on
+// immediately instead of waiting out the rest of the dwell/retransmits.
+func (s *SYNScanner) Scan(ctx context.Context, hosts []string, ports []int, dwell time.Duration) (map[string][]PortResult, error) {
+	if s.conn6 == nil {
+		for _, h := range hosts {
+			if ip := net.ParseIP(h); ip != nil && ip.To4() == nil {
+				return nil, fmt.Errorf("scan: %s is IPv6 but no raw ip6 socket is available", h)
+			}
+		}
+	}
+
+	results := make(map[string][]PortResult)
+	var mu sync.Mutex
+
+	pending := newPendingSet(hosts, ports)
+	for attempt := 0; attempt <= maxRetransmits && len(pending) > 0 && ctx.Err() == nil; attempt++ {
+		roundHosts, roundPorts := pending.hostsAndPorts()
+
+		done := make(chan struct{})
+		var recvWG sync.WaitGroup
+		recvWG.Add(1)
+		go func() {
+			defer recvWG.Done()
+			s.receive(roundHosts, done, results, &mu)
+		}()
+
+		s.send(ctx, roundHosts, roundPorts)
+
+		// Back off a little more each retransmit round, in case the loss
+		// was caused by a congested link rather than a single bad packet.
+		select {
+		case <-time.After(dwell + time.Duration(attempt)*dwell/2):
+		case <-ctx.Done():
+		}
+		close(done)
+		recvWG.Wait()
+
+		pending = pending.minus(results)
+	}
+
+	return results, nil
+}
+
+// hostPort identifies one probe in a pending set.
+type hostPort struct {
+	host string
+	port int
+}
+
+// pendingSet tracks which host:port probes have not yet gotten a reply.
+type pendingSet map[hostPort]bool
+
+func newPendingSet(hosts []string, ports []int) pendingSet {
+	p := make(pendingSet, len(hosts)*len(ports))
+	for _, h := range hosts {
+		for _, port := range ports {
+			p[hostPort{h, port}] = true
+		}
+	}
+	return p
+}
+
+// hostsAndPorts flattens the set back into the host/port lists send/
+// receive expect: every host still missing at least one reply, probed
+// against the union of all still-pending ports. On a retransmit round
+// this can resend a few already-answered host:port pairs, which is a
+// fine trade against the complexity of tracking an exact per-host
+// remainder; replies for already-answered pairs are simply deduplicated
+// by minus() afterwards.
+func (p pendingSet) hostsAndPorts() ([]string, []int) {
+	hostSet := make(map[string]bool)
+	portSet := make(map[int]bool)
+	for hp := range p {
+		hostSet[hp.host] = true
+		portSet[hp.port] = true
+	}
+
+	hosts := make([]string, 0, len(hostSet))
+	for h := range hostSet {
+		hosts = append(hosts, h)
+	}
+	ports := make([]int, 0, len(portSet))
+	for port := range portSet {
+		ports = append(ports, port)
+	}
+	return hosts, ports
+}
+
+// minus drops every host:port already present in results (whether open
+// or closed), leaving only the probes still awaiting a reply.
+func (p pendingSet) minus(results map[string][]PortResult) pendingSet {
+	answered := make(map[hostPort]bool)
+	for host, ports := range results {
+		for _, r := range ports {
+			answered[hostPort{host, r.Port}] = true
+		}
+	}
+	remaining := make(pendingSet)
+	for hp := range p {
+		if !answered[hp] {
+			remaining[hp] = true
+		}
+	}
+	return remaining
+}
+
+// send emits one SYN per host:port, rate limited by a simple token
+// bucket, and backs off when the OS reports the send buffer is full.
+// It stops early if ctx is cancelled mid-round.
+func (s *SYNScanner) send(ctx context.Context, hosts []string, ports []int) {
+	interval := time.Second / time.Duration(s.rate)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for _, host := range hosts {
+		if ctx.Err() != nil {
+			return
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			continue
+		}
+		conn, v6 := s.connFor(ip)
+		if conn == nil {
+			continue
+		}
+		srcIP, err := localSourceIP(ip, v6)
+		if err != nil {
+			// No route to this host: every checksum we'd bake in would be
+			// wrong anyway, so skip it rather than send packets real hosts
+			// will silently drop as corrupt.
+			continue
+		}
+		for _, port := range ports {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			pkt, _, _ := buildSYN(ip, port, v6, srcIP)
+			if _, err := conn.WriteToIP(pkt, &net.IPAddr{IP: ip}); err != nil {
+				// Adaptive backoff: the kernel send buffer is full: slow
+				// down and retry once rather than dropping the probe.
+				if isEAGAIN(err) {
+					time.Sleep(10 * time.Millisecond)
+					conn.WriteToIP(pkt, &net.IPAddr{IP: ip})
+				}
+			}
+		}
+	}
+}
+
+// connFor returns the raw socket to use for ip, and whether it's IPv6.
+func (s *SYNScanner) connFor(ip net.IP) (*net.IPConn, bool) {
+	if ip.To4() != nil {
+		return s.conn4, false
+	}
+	return s.conn6, true
+}
+
+// receive reads replies until done is closed, validating each one against
+// the stateless encoding applied by buildSYN/expectedSeq.
+func (s *SYNScanner) receive(hosts []string, done <-chan struct{}, results map[string][]PortResult, mu *sync.Mutex) {
+	expected := make(map[string]bool, len(hosts))
+	var wantV4, wantV6 bool
+	for _, h := range hosts {
+		expected[h] = true
+		if ip := net.ParseIP(h); ip != nil {
+			if ip.To4() != nil {
+				wantV4 = true
+			} else {
+				wantV6 = true
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	if wantV4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.receiveOn(s.conn4, expected, done, results, mu)
+		}()
+	}
+	if wantV6 && s.conn6 != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.receiveOn(s.conn6, expected, done, results, mu)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *SYNScanner) receiveOn(conn *net.IPConn, expected map[string]bool, done <-chan struct{}, results map[string][]PortResult, mu *sync.Mutex) {
+	buf := make([]byte, 4096)
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, ip, err := conn.ReadFromIP(buf)
+		if err != nil || !expected[ip.IP.String()] {
+			continue
+		}
+
+		tcp := parseTCP(buf[:n])
+		if tcp == nil {
+			continue
+		}
+
+		if int(tcp.DstPort) != expectedSrcPort(ip.IP, int(tcp.SrcPort)) {
+			continue
+		}
+		if tcp.Ack != expectedSeq(ip.IP, int(tcp.SrcPort))+1 {
+			continue
+		}
+
+		state := ""
+		switch {
+		case tcp.SYN && tcp.ACK:
+			state = "open"
+		case tcp.RST:
+			state = "closed"
+		default:
+			continue
+		}
+
+		mu.Lock()
+		results[ip.IP.String()] = append(results[ip.IP.String()], PortResult{Port: int(tcp.SrcPort), State: state})
+		mu.Unlock()
+	}
+}
+
+// buildSYN serializes a SYN segment for ip:port, encoding the target into
+// the source port and initial sequence number so replies are self
+// describing and the scanner needn't track per-target state. srcIP must
+// be the actual address the kernel will send the packet from: the raw
+// IP socket is opened without IP_HDRINCL, so the kernel fills in the real
+// source address on the wire but never recomputes our TCP checksum, which
+// is computed over a pseudo-header that includes the source address.
+func buildSYN(ip net.IP, port int, v6 bool, srcIP net.IP) (pkt []byte, srcPort int, seq uint32) {
+	srcPort = expectedSrcPort(ip, port)
+	seq = expectedSeq(ip, port)
+
+	tcpLayer := &layers.TCP{
+		SrcPort: layers.TCPPort(srcPort),
+		DstPort: layers.TCPPort(port),
+		Seq:     seq,
+		SYN:     true,
+		Window:  65535,
+	}
+
+	var network gopacket.NetworkLayer
+	if v6 {
+		ipLayer := &layers.IPv6{
+			SrcIP:      srcIP,
+			DstIP:      ip,
+			NextHeader: layers.IPProtocolTCP,
+		}
+		network = ipLayer
+	} else {
+		ipLayer := &layers.IPv4{
+			SrcIP:    srcIP,
+			DstIP:    ip,
+			Protocol: layers.IPProtocolTCP,
+		}
+		network = ipLayer
+	}
+	tcpLayer.SetNetworkLayerForChecksum(network)
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+	gopacket.SerializeLayers(buf, opts, tcpLayer)
+
+	return buf.Bytes(), srcPort, seq
+}
+
+// localSourceIP determines the address the kernel will actually use as
+// the source when sending to dst, by letting the routing table pick one
+// for a connected UDP socket (which never sends a packet of its own).
+// That address is what has to go into the checksum's pseudo-header, since
+// the raw socket's kernel-filled source address isn't known until send
+// time and won't match a zero or wildcard address baked in ahead of time.
+func localSourceIP(dst net.IP, v6 bool) (net.IP, error) {
+	network := "udp4"
+	if v6 {
+		network = "udp6"
+	}
+	conn, err := net.Dial(network, net.JoinHostPort(dst.String(), "9"))
+	if err != nil {
+		return nil, fmt.Errorf("scan: no route to %s: %w", dst, err)
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// parseTCP decodes a raw TCP segment as delivered by an IPPROTO_TCP raw
+// socket (no IPv4/IPv6 header).
+func parseTCP(raw []byte) *layers.TCP {
+	tcp := &layers.TCP{}
+	if err := tcp.DecodeFromBytes(raw, gopacket.NilDecodeFeedback); err != nil {
+		return nil
+	}
+	return tcp
+}
+
+// expectedSrcPort/expectedSeq derive a per-target source port and
+// sequence number from a simple hash of host:port, so the scanner can
+// validate a reply without having stored any per-target state.
+func expectedSrcPort(ip net.IP, port int) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d:port", ip.String(), port)
+	return 20000 + int(h.Sum32()%20000)
+}
+
+func expectedSeq(ip net.IP, port int) uint32 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d:seq", ip.String(), port)
+	return h.Sum32()
+}
+
+// isEAGAIN reports whether err is the kernel telling us the send buffer
+// is momentarily full.
+func isEAGAIN(err error) bool {
+	return errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EWOULDBLOCK)
+}
@@ -0,0 +1,16 @@
+package serviceprobe
+
+// wellKnownNames covers ports the registry has no protocol-aware Probe
+// for (no handshake worth speaking, or a plain name lookup is all the
+// old scanner ever did). Identify falls back to this table only after
+// every registered Probe has declined to claim the port.
+var wellKnownNames = map[int]string{
+	23:   "telnet",
+	53:   "dns",
+	110:  "pop3",
+	135:  "rpc",
+	139:  "netbios",
+	143:  "imap",
+	1723: "pptp",
+	5900: "vnc",
+}
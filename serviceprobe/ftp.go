@@ -0,0 +1,29 @@
+package serviceprobe
+
+import (
+	"context"
+	"strings"
+)
+
+func init() { Register(ftpProbe{}) }
+
+type ftpProbe struct{}
+
+func (ftpProbe) Name() string        { return "ftp" }
+func (ftpProbe) DefaultPorts() []int { return []int{21} }
+
+func (ftpProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	line, _ := readLine(conn, DefaultTimeout)
+	line = strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(line, "220") {
+		return ServiceInfo{}, nil, nil
+	}
+
+	return ServiceInfo{Name: "ftp", Banner: line}, nil, nil
+}
@@ -0,0 +1,37 @@
+package serviceprobe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func init() { Register(httpProbe{}) }
+
+type httpProbe struct{}
+
+func (httpProbe) Name() string        { return "http" }
+func (httpProbe) DefaultPorts() []int { return []int{80, 8080} }
+
+// Probe issues a plain GET and reports the Server header as the banner,
+// the same signal the old hardcoded HTTP banner grab used.
+func (httpProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	client := &http.Client{Timeout: DefaultTimeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s:%d", host, port), nil)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+	defer resp.Body.Close()
+
+	info := ServiceInfo{Name: "http"}
+	if server := resp.Header.Get("Server"); server != "" {
+		info.Banner = server
+	}
+
+	return info, nil, nil
+}
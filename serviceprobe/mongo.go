@@ -0,0 +1,159 @@
+package serviceprobe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+func init() { Register(mongoProbe{}) }
+
+type mongoProbe struct{}
+
+func (mongoProbe) Name() string        { return "mongodb" }
+func (mongoProbe) DefaultPorts() []int { return []int{27017} }
+
+// Probe sends an isMaster command framed as an OP_MSG (the wire protocol
+// MongoDB 6.0+ speaks; OP_QUERY was removed) and pulls the "version"
+// field out of the BSON reply.
+func (mongoProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(buildIsMasterOpMsg()); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	header := make([]byte, 16)
+	if _, err := readFull(conn, header); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+	msgLen := binary.LittleEndian.Uint32(header[0:4])
+	opCode := binary.LittleEndian.Uint32(header[12:16])
+	if opCode != 2013 || msgLen < 21 {
+		return ServiceInfo{}, nil, nil
+	}
+
+	body := make([]byte, msgLen-16)
+	if _, err := readFull(conn, body); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+	if len(body) < 5 || body[4] != 0x00 { // flagBits(4) + kind byte (0 == body document)
+		return ServiceInfo{}, nil, nil
+	}
+
+	doc := body[5:]
+	version, _ := bsonStringField(doc, "version")
+	if version == "" {
+		return ServiceInfo{Name: "mongodb"}, nil, nil
+	}
+	return ServiceInfo{Name: "mongodb", Version: version}, nil, nil
+}
+
+func readFull(conn interface{ Read([]byte) (int, error) }, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := conn.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// buildIsMasterOpMsg hand-assembles the minimal OP_MSG for
+// { isMaster: 1, $db: "admin" }.
+func buildIsMasterOpMsg() []byte {
+	var doc bytes.Buffer
+
+	// isMaster: 1 (int32)
+	doc.WriteByte(0x10)
+	doc.WriteString("isMaster\x00")
+	binary.Write(&doc, binary.LittleEndian, int32(1))
+
+	// $db: "admin" (string)
+	doc.WriteByte(0x02)
+	doc.WriteString("$db\x00")
+	binary.Write(&doc, binary.LittleEndian, int32(len("admin")+1))
+	doc.WriteString("admin\x00")
+
+	doc.WriteByte(0x00) // document terminator
+
+	fullDoc := make([]byte, 4+doc.Len())
+	binary.LittleEndian.PutUint32(fullDoc[0:4], uint32(len(fullDoc)))
+	copy(fullDoc[4:], doc.Bytes())
+
+	var msg bytes.Buffer
+	msg.Write(make([]byte, 16))                        // header, patched below
+	binary.Write(&msg, binary.LittleEndian, uint32(0)) // flagBits
+	msg.WriteByte(0x00)                                // kind: body
+	msg.Write(fullDoc)
+
+	out := msg.Bytes()
+	binary.LittleEndian.PutUint32(out[0:4], uint32(len(out))) // messageLength
+	binary.LittleEndian.PutUint32(out[4:8], 1)                // requestID
+	binary.LittleEndian.PutUint32(out[8:12], 0)               // responseTo
+	binary.LittleEndian.PutUint32(out[12:16], 2013)           // opCode: OP_MSG
+
+	return out
+}
+
+// bsonStringField does just enough BSON parsing to find a top-level
+// string field by key, without pulling in a full BSON library for a
+// single probe.
+func bsonStringField(doc []byte, key string) (string, bool) {
+	if len(doc) < 5 {
+		return "", false
+	}
+	i := 4 // skip document length
+	for i < len(doc) && doc[i] != 0x00 {
+		elemType := doc[i]
+		i++
+
+		start := i
+		for i < len(doc) && doc[i] != 0x00 {
+			i++
+		}
+		if i >= len(doc) {
+			return "", false
+		}
+		name := string(doc[start:i])
+		i++ // skip key terminator
+
+		switch elemType {
+		case 0x02: // UTF-8 string
+			if i+4 > len(doc) {
+				return "", false
+			}
+			strLen := int(binary.LittleEndian.Uint32(doc[i : i+4]))
+			i += 4
+			if i+strLen > len(doc) {
+				return "", false
+			}
+			value := string(doc[i : i+strLen-1]) // drop trailing NUL
+			i += strLen
+			if name == key {
+				return value, true
+			}
+		case 0x10: // int32
+			i += 4
+		case 0x01: // double
+			i += 8
+		case 0x12: // int64
+			i += 8
+		case 0x08: // bool
+			i++
+		default:
+			// Unknown/unsupported element type for this minimal parser.
+			return "", false
+		}
+	}
+	return "", false
+}
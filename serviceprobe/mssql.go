@@ -0,0 +1,104 @@
+package serviceprobe
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+func init() { Register(mssqlProbe{}) }
+
+type mssqlProbe struct{}
+
+func (mssqlProbe) Name() string        { return "mssql" }
+func (mssqlProbe) DefaultPorts() []int { return []int{1433} }
+
+// Probe sends a minimal TDS PRELOGIN packet (the handshake every SQL
+// Server speaks before authentication) and pulls the server's version
+// out of the PRELOGIN response's VERSION option.
+func (mssqlProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(buildPrelogin()); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil || n < 8 || buf[0] != 0x04 { // 0x04 == TDS "Tabular result" (PRELOGIN response)
+		return ServiceInfo{}, nil, nil
+	}
+
+	version := parsePreloginVersion(buf[8:n])
+	if version == "" {
+		return ServiceInfo{Name: "mssql"}, nil, nil
+	}
+	return ServiceInfo{Name: "mssql", Version: version}, nil, nil
+}
+
+func buildPrelogin() []byte {
+	const (
+		tokenVersion    = 0x00
+		tokenEncryption = 0x01
+		tokenTerminator = 0xff
+	)
+
+	versionData := []byte{0, 0, 0, 0, 0, 0}
+	encryptionData := []byte{0x02} // ENCRYPT_NOT_SUP
+
+	tokenTableLen := 2*5 + 1
+	payload := make([]byte, 0, tokenTableLen+len(versionData)+len(encryptionData))
+
+	versionOff := tokenTableLen
+	encryptionOff := versionOff + len(versionData)
+
+	payload = append(payload, tokenVersion)
+	payload = binary.BigEndian.AppendUint16(payload, uint16(versionOff))
+	payload = binary.BigEndian.AppendUint16(payload, uint16(len(versionData)))
+
+	payload = append(payload, tokenEncryption)
+	payload = binary.BigEndian.AppendUint16(payload, uint16(encryptionOff))
+	payload = binary.BigEndian.AppendUint16(payload, uint16(len(encryptionData)))
+
+	payload = append(payload, tokenTerminator)
+	payload = append(payload, versionData...)
+	payload = append(payload, encryptionData...)
+
+	header := []byte{
+		0x12, // type: PRELOGIN
+		0x01, // status: EOM
+		0, 0, // length, filled below
+		0, 0, // SPID
+		0x01, // packet ID
+		0x00, // window
+	}
+	binary.BigEndian.PutUint16(header[2:4], uint16(len(header)+len(payload)))
+
+	return append(header, payload...)
+}
+
+// parsePreloginVersion walks the option token table in a PRELOGIN
+// response payload looking for the VERSION option.
+func parsePreloginVersion(payload []byte) string {
+	for i := 0; i+5 <= len(payload); i += 5 {
+		token := payload[i]
+		if token == 0xff {
+			break
+		}
+		offset := binary.BigEndian.Uint16(payload[i+1 : i+3])
+		length := binary.BigEndian.Uint16(payload[i+3 : i+5])
+
+		if token == 0x00 && length >= 4 && int(offset)+4 <= len(payload) {
+			v := payload[offset : offset+4]
+			return fmt.Sprintf("%d.%d.%d", v[0], v[1], binary.BigEndian.Uint16(v[2:4]))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package serviceprobe
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+func init() { Register(mysqlProbe{}) }
+
+type mysqlProbe struct{}
+
+func (mysqlProbe) Name() string        { return "mysql" }
+func (mysqlProbe) DefaultPorts() []int { return []int{3306} }
+
+// Probe reads the server's initial MySQL handshake packet. The wire
+// format is: 3-byte length, 1-byte sequence id, then a payload starting
+// with a 1-byte protocol version followed by a NUL-terminated version
+// string (see the MySQL Client/Server Protocol docs).
+func (mysqlProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	if err != nil || n < 6 {
+		return ServiceInfo{}, nil, nil
+	}
+	buf = buf[:n]
+
+	payload := buf[4:]
+	if len(payload) < 2 {
+		return ServiceInfo{}, nil, nil
+	}
+
+	protoVersion := payload[0]
+	if protoVersion != 0x0a { // protocol version 10 is what every modern MySQL/MariaDB speaks
+		return ServiceInfo{}, nil, nil
+	}
+
+	end := bytes.IndexByte(payload[1:], 0x00)
+	if end < 0 {
+		return ServiceInfo{}, nil, nil
+	}
+	version := string(payload[1 : 1+end])
+
+	return ServiceInfo{Name: "mysql", Version: version}, nil, nil
+}
@@ -0,0 +1,54 @@
+package serviceprobe
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+func init() { Register(postgresProbe{}) }
+
+type postgresProbe struct{}
+
+func (postgresProbe) Name() string        { return "postgresql" }
+func (postgresProbe) DefaultPorts() []int { return []int{5432} }
+
+// Probe sends an SSLRequest packet, the one message a Postgres server
+// will always answer unsolicited with a single 'S' (supported) or 'N'
+// (not supported) byte. That's enough to positively identify the
+// service without completing a full startup/auth handshake.
+func (postgresProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], 80877103) // SSLRequest code
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(req); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	resp := make([]byte, 1)
+	if _, err := conn.Read(resp); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	if resp[0] != 'S' && resp[0] != 'N' {
+		return ServiceInfo{}, nil, nil
+	}
+
+	info := ServiceInfo{Name: "postgresql"}
+	if resp[0] == 'S' {
+		info.Banner = "TLS supported"
+	} else {
+		info.Banner = "TLS not supported"
+	}
+
+	return info, nil, nil
+}
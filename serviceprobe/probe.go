@@ -0,0 +1,108 @@
+// Package serviceprobe implements pluggable, per-service fingerprinting:
+// each Probe grabs a banner or speaks just enough of a protocol's
+// handshake to identify the service and version running on a port, and
+// optionally reports security findings observed along the way (e.g. a
+// SMBv1 dialect still being offered).
+package serviceprobe
+
+import (
+	"context"
+	"time"
+)
+
+// ServiceInfo is what a Probe learns about the service on a port.
+type ServiceInfo struct {
+	Name    string // e.g. "ssh", "mysql", "https"
+	Version string
+	Banner  string
+}
+
+// Finding is a security-relevant observation attached to a port, e.g. an
+// outdated protocol dialect or a known CVE signature.
+type Finding struct {
+	Name     string
+	Severity string // "info", "low", "medium", "high", "critical"
+	Evidence string
+	CVE      string
+}
+
+// Probe fingerprints the service listening on host:port.
+type Probe interface {
+	// Name identifies the probe itself (used for registration/logging),
+	// not necessarily the service name returned in ServiceInfo.
+	Name() string
+	// DefaultPorts lists the ports this probe is tried against by
+	// default, e.g. {22} for SSH. Probes registered by content sniffing
+	// alone may return nil.
+	DefaultPorts() []int
+	// Probe speaks to host:port and reports what it found. err is only
+	// returned for transport-level failures (dial/timeout); a
+	// non-matching service is reported via an empty ServiceInfo.Name.
+	Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error)
+}
+
+var registry []Probe
+
+// Register adds a probe to the default set consulted by Identify.
+func Register(p Probe) {
+	registry = append(registry, p)
+}
+
+// byPort maps a port to the probes that default to it, built lazily from
+// the registry so init-order across files doesn't matter.
+func byPort(port int) []Probe {
+	var matches []Probe
+	for _, p := range registry {
+		for _, dp := range p.DefaultPorts() {
+			if dp == port {
+				matches = append(matches, p)
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// DefaultTimeout bounds every probe dial/read.
+const DefaultTimeout = 5 * time.Second
+
+// Identify tries every probe registered against port (by default port,
+// falling back to the full registry so services on non-standard ports
+// still get sniffed) and returns the first one that recognizes the
+// service.
+func Identify(host string, port int) (ServiceInfo, []Finding) {
+	candidates := byPort(port)
+	tried := make(map[Probe]bool, len(candidates))
+	for _, p := range candidates {
+		tried[p] = true
+		if info, findings, ok := tryProbe(p, host, port); ok {
+			return info, findings
+		}
+	}
+
+	for _, p := range registry {
+		if tried[p] {
+			continue
+		}
+		if info, findings, ok := tryProbe(p, host, port); ok {
+			return info, findings
+		}
+	}
+
+	if name, ok := wellKnownNames[port]; ok {
+		return ServiceInfo{Name: name}, nil
+	}
+
+	return ServiceInfo{}, nil
+}
+
+// tryProbe runs p with its own fresh DefaultTimeout deadline, so one probe
+// blocking until timeout (e.g. a filtered port) can't exhaust the budget
+// of every later probe tried in the same Identify call.
+func tryProbe(p Probe, host string, port int) (ServiceInfo, []Finding, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	info, findings, err := p.Probe(ctx, host, port)
+	return info, findings, err == nil && info.Name != ""
+}
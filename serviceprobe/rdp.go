@@ -0,0 +1,94 @@
+package serviceprobe
+
+import (
+	"context"
+	"time"
+)
+
+func init() { Register(rdpProbe{}) }
+
+type rdpProbe struct{}
+
+func (rdpProbe) Name() string        { return "rdp" }
+func (rdpProbe) DefaultPorts() []int { return []int{3389} }
+
+// Probe sends an X.224 Connection Request carrying an RDP Negotiation
+// Request that advertises support for CredSSP (NLA) and TLS, then reads
+// back which security protocol the server selected.
+func (rdpProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write(x224ConnectionRequest()); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	buf := make([]byte, 256)
+	n, err := conn.Read(buf)
+	if err != nil || n < 19 {
+		return ServiceInfo{}, nil, nil
+	}
+	resp := buf[:n]
+
+	// TPKT(4) + X.224 CC TPDU header(7) + RDP_NEG_RSP(8): the selected
+	// protocol is the last 4-byte field.
+	if resp[5] != 0xd0 { // X.224 Connection Confirm
+		return ServiceInfo{}, nil, nil
+	}
+
+	info := ServiceInfo{Name: "rdp"}
+	var findings []Finding
+	if len(resp) >= 19 {
+		selected := resp[15]
+		switch selected {
+		case 0x00:
+			info.Version = "RDP standard security (no TLS/NLA)"
+			findings = append(findings, Finding{
+				Name:     "RDP server accepts legacy standard RDP security",
+				Severity: "medium",
+				Evidence: "server selected protocol 0x00 instead of TLS/CredSSP",
+			})
+		case 0x01:
+			info.Version = "TLS security"
+		case 0x02, 0x03:
+			info.Version = "CredSSP (NLA) security"
+		default:
+			info.Version = "unknown negotiated protocol"
+		}
+	}
+
+	return info, findings, nil
+}
+
+// x224ConnectionRequest builds a TPKT-framed X.224 Connection Request
+// with an embedded RDP_NEG_REQ advertising PROTOCOL_SSL | PROTOCOL_HYBRID.
+func x224ConnectionRequest() []byte {
+	negReq := []byte{
+		0x01,       // type: RDP_NEG_REQ
+		0x00,       // flags
+		0x08, 0x00, // length (LE) = 8
+		0x03, 0x00, 0x00, 0x00, // requestedProtocols: SSL | HYBRID (LE)
+	}
+
+	x224 := []byte{
+		0x00,       // length indicator, patched below
+		0xe0,       // CR TPDU code
+		0x00, 0x00, // dst-ref
+		0x00, 0x00, // src-ref
+		0x00, // class/options
+	}
+	x224 = append(x224, negReq...)
+	x224[0] = byte(len(x224) - 1)
+
+	tpkt := []byte{0x03, 0x00, 0x00, 0x00} // version, reserved, length (patched below)
+	full := append(tpkt, x224...)
+	full[2] = byte(len(full) >> 8)
+	full[3] = byte(len(full))
+
+	return full
+}
@@ -0,0 +1,71 @@
+package serviceprobe
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"time"
+)
+
+func init() { Register(redisProbe{}) }
+
+type redisProbe struct{}
+
+func (redisProbe) Name() string        { return "redis" }
+func (redisProbe) DefaultPorts() []int { return []int{6379} }
+
+// Probe issues an inline INFO command. On an unauthenticated instance
+// this returns the full server info block (and is itself the finding:
+// Redis with no AUTH configured); on an authenticated one it returns a
+// NOAUTH error, which we still report as a positive identification.
+func (redisProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	if _, err := conn.Write([]byte("INFO\r\n")); err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetReadDeadline(time.Now().Add(DefaultTimeout))
+	reader := bufio.NewReader(conn)
+	first, err := reader.ReadString('\n')
+	if err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+
+	if strings.HasPrefix(first, "-NOAUTH") || strings.HasPrefix(first, "-ERR") && strings.Contains(first, "AUTH") {
+		return ServiceInfo{Name: "redis", Banner: strings.TrimSpace(first)}, nil, nil
+	}
+
+	if !strings.HasPrefix(first, "$") {
+		return ServiceInfo{}, nil, nil
+	}
+
+	info := ServiceInfo{Name: "redis"}
+	var findings []Finding
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "redis_version:") {
+			info.Version = strings.TrimPrefix(line, "redis_version:")
+		}
+		if line == "" && info.Version != "" {
+			break
+		}
+	}
+
+	findings = append(findings, Finding{
+		Name:     "Redis accessible without authentication",
+		Severity: "high",
+		Evidence: "INFO command succeeded with no AUTH required",
+	})
+
+	return info, findings, nil
+}
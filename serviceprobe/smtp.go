@@ -0,0 +1,40 @@
+package serviceprobe
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+func init() { Register(smtpProbe{}) }
+
+type smtpProbe struct{}
+
+func (smtpProbe) Name() string        { return "smtp" }
+func (smtpProbe) DefaultPorts() []int { return []int{25, 587} }
+
+func (smtpProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	banner, _ := readLine(conn, DefaultTimeout)
+	banner = strings.TrimRight(banner, "\r\n")
+	if !strings.HasPrefix(banner, "220") {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(DefaultTimeout))
+	conn.Write([]byte("EHLO pdive2.local\r\n"))
+	ehlo, _ := readLine(conn, DefaultTimeout)
+	ehlo = strings.TrimRight(ehlo, "\r\n")
+
+	info := ServiceInfo{Name: "smtp", Banner: banner}
+	if ehlo != "" {
+		info.Version = ehlo
+	}
+
+	return info, nil, nil
+}
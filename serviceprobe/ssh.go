@@ -0,0 +1,44 @@
+package serviceprobe
+
+import (
+	"context"
+	"strings"
+)
+
+func init() { Register(sshProbe{}) }
+
+type sshProbe struct{}
+
+func (sshProbe) Name() string        { return "ssh" }
+func (sshProbe) DefaultPorts() []int { return []int{22} }
+
+func (sshProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	conn, err := dial(ctx, host, port)
+	if err != nil {
+		return ServiceInfo{}, nil, err
+	}
+	defer conn.Close()
+
+	line, _ := readLine(conn, DefaultTimeout)
+	line = strings.TrimRight(line, "\r\n")
+	if !strings.HasPrefix(line, "SSH-") {
+		return ServiceInfo{}, nil, nil
+	}
+
+	info := ServiceInfo{Name: "ssh", Banner: line}
+	parts := strings.SplitN(line, "-", 3)
+	if len(parts) == 3 {
+		info.Version = parts[2]
+	}
+
+	var findings []Finding
+	if strings.HasPrefix(line, "SSH-1.") {
+		findings = append(findings, Finding{
+			Name:     "SSH protocol 1.x offered",
+			Severity: "high",
+			Evidence: line,
+		})
+	}
+
+	return info, findings, nil
+}
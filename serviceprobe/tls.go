@@ -0,0 +1,75 @@
+package serviceprobe
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() { Register(tlsProbe{}) }
+
+type tlsProbe struct{}
+
+func (tlsProbe) Name() string        { return "tls" }
+func (tlsProbe) DefaultPorts() []int { return []int{443, 8443, 993, 995, 465} }
+
+// Probe completes a TLS handshake, collects the certificate's SANs, and
+// computes a JA3S-style fingerprint (a JA3 of the server's negotiated
+// version/cipher, since a scanner observes the ServerHello, not a client
+// it controls - true JA3 fingerprints the client's hello).
+func (tlsProbe) Probe(ctx context.Context, host string, port int) (ServiceInfo, []Finding, error) {
+	d := tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}}
+	conn, err := d.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		return ServiceInfo{}, nil, nil
+	}
+	defer conn.Close()
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return ServiceInfo{}, nil, nil
+	}
+
+	conn.SetDeadline(time.Now().Add(DefaultTimeout))
+	state := tlsConn.ConnectionState()
+
+	var sans []string
+	if len(state.PeerCertificates) > 0 {
+		sans = append(sans, state.PeerCertificates[0].DNSNames...)
+	}
+
+	info := ServiceInfo{
+		Name:    "tls",
+		Version: tlsVersionName(state.Version),
+		Banner:  fmt.Sprintf("ja3s=%s sans=%s", ja3s(state), strings.Join(sans, ",")),
+	}
+
+	return info, nil, nil
+}
+
+func tlsVersionName(v uint16) string {
+	switch v {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return "unknown"
+	}
+}
+
+// ja3s hashes the negotiated version and cipher suite the same way a
+// real JA3S fingerprint does, minus the extension list Go's crypto/tls
+// doesn't expose for a completed handshake.
+func ja3s(state tls.ConnectionState) string {
+	raw := fmt.Sprintf("%d,%d", state.Version, state.CipherSuite)
+	sum := md5.Sum([]byte(raw))
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,23 @@
+package serviceprobe
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"time"
+)
+
+// dial opens a TCP connection to host:port honoring ctx's deadline.
+func dial(ctx context.Context, host string, port int) (net.Conn, error) {
+	d := net.Dialer{}
+	return d.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// readLine reads a single CRLF/LF-terminated line, or whatever arrived
+// before timeout elapses.
+func readLine(conn net.Conn, timeout time.Duration) (string, error) {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	return line, err
+}
@@ -0,0 +1,121 @@
+// Package state persists incremental scan progress to an on-disk bbolt
+// file, so a PDive2 run interrupted by Ctrl+C (or any SIGINT/SIGTERM) can
+// be resumed later with --resume instead of starting over.
+package state
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var progressBucket = []byte("progress")
+var progressKey = []byte("current")
+
+// Progress is the incremental scan state checkpointed after each phase.
+// It is deliberately coarse-grained (per-host, not per-port): a phase's
+// worker loop is already the unit of work the rest of PDive2 treats as
+// atomic, so resuming at that granularity is enough to avoid losing
+// hours of scanning without adding a second bookkeeping scheme.
+type Progress struct {
+	ResumeToken string `json:"resume_token"`
+
+	HostDiscoveryDone bool     `json:"host_discovery_done"`
+	HostsUp           []string `json:"hosts_up"`
+	HostsDown         []string `json:"hosts_down"`
+
+	PassiveDone  bool     `json:"passive_done"`
+	PassiveHosts []string `json:"passive_hosts"`
+
+	PortScanDone map[string]bool `json:"port_scan_done"`
+
+	ServiceEnumDone map[string]bool `json:"service_enum_done"`
+
+	NmapDone map[string]bool `json:"nmap_done"`
+}
+
+// NewProgress returns an empty Progress stamped with a fresh resume token.
+func NewProgress() *Progress {
+	return &Progress{
+		ResumeToken:     newResumeToken(),
+		PortScanDone:    make(map[string]bool),
+		ServiceEnumDone: make(map[string]bool),
+		NmapDone:        make(map[string]bool),
+	}
+}
+
+func newResumeToken() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unresumable"
+	}
+	return fmt.Sprintf("%x", b)
+}
+
+// Store is an on-disk bbolt-backed holder for a single scan's Progress.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens (creating if necessary) the state file at path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(progressBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize state store %s: %w", path, err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying state file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Load returns the previously checkpointed Progress, or nil if the store
+// has none yet.
+func (s *Store) Load() (*Progress, error) {
+	var p *Progress
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(progressBucket).Get(progressKey)
+		if data == nil {
+			return nil
+		}
+		p = &Progress{}
+		return json.Unmarshal(data, p)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scan progress: %w", err)
+	}
+
+	return p, nil
+}
+
+// Save checkpoints p to disk, overwriting any previously saved progress.
+func (s *Store) Save(p *Progress) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan progress: %w", err)
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(progressBucket).Put(progressKey, data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save scan progress: %w", err)
+	}
+
+	return nil
+}
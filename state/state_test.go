@@ -0,0 +1,104 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNewProgress(t *testing.T) {
+	p := NewProgress()
+
+	if p.ResumeToken == "" {
+		t.Error("ResumeToken should not be empty")
+	}
+	for name, m := range map[string]map[string]bool{
+		"PortScanDone":    p.PortScanDone,
+		"ServiceEnumDone": p.ServiceEnumDone,
+		"NmapDone":        p.NmapDone,
+	} {
+		if m == nil {
+			t.Errorf("%s should be initialized, got nil", name)
+		}
+	}
+}
+
+func TestStore_SaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+
+	if got, err := store.Load(); err != nil || got != nil {
+		t.Fatalf("Load on a fresh store = (%v, %v), want (nil, nil)", got, err)
+	}
+
+	p := NewProgress()
+	p.HostDiscoveryDone = true
+	p.HostsUp = []string{"10.0.0.5"}
+	p.PortScanDone["10.0.0.5"] = true
+	p.NmapDone["10.0.0.5"] = true
+
+	if err := store.Save(p); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got == nil {
+		t.Fatal("Load after Save = nil, want the saved Progress")
+	}
+	if got.ResumeToken != p.ResumeToken {
+		t.Errorf("ResumeToken = %q, want %q", got.ResumeToken, p.ResumeToken)
+	}
+	if !got.HostDiscoveryDone {
+		t.Error("HostDiscoveryDone = false, want true")
+	}
+	if !got.PortScanDone["10.0.0.5"] {
+		t.Error("PortScanDone[10.0.0.5] = false, want true")
+	}
+	if !got.NmapDone["10.0.0.5"] {
+		t.Error("NmapDone[10.0.0.5] = false, want true")
+	}
+}
+
+func TestStore_SaveOverwritesPreviousProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+
+	first := NewProgress()
+	first.PassiveDone = true
+	if err := store.Save(first); err != nil {
+		t.Fatalf("Save(first): %v", err)
+	}
+
+	second := NewProgress()
+	second.PassiveDone = false
+	second.HostDiscoveryDone = true
+	if err := store.Save(second); err != nil {
+		t.Fatalf("Save(second): %v", err)
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.PassiveDone {
+		t.Error("PassiveDone = true, want false (overwritten by second Save)")
+	}
+	if !got.HostDiscoveryDone {
+		t.Error("HostDiscoveryDone = false, want true (from second Save)")
+	}
+	if got.ResumeToken != second.ResumeToken {
+		t.Errorf("ResumeToken = %q, want %q", got.ResumeToken, second.ResumeToken)
+	}
+}
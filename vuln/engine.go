@@ -0,0 +1,321 @@
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a single request (HTTP fetch or TCP round-trip).
+const DefaultTimeout = 5 * time.Second
+
+// Finding is one confirmed match of a Template's request against a
+// host:port pair.
+type Finding struct {
+	TemplateID string
+	Severity   Severity
+	Host       string
+	Port       int
+	MatchedAt  string // the URL (HTTP) or host:port (TCP) that was probed
+	Extracted  []string
+}
+
+// Engine runs a fixed set of Templates against host:port targets.
+type Engine struct {
+	templates   []Template
+	minSeverity Severity
+	httpClient  *http.Client
+}
+
+// NewEngine returns an Engine that will run templates whose severity is
+// at least minSeverity.
+func NewEngine(templates []Template, minSeverity Severity) *Engine {
+	return &Engine{
+		templates:   templates,
+		minSeverity: minSeverity,
+		httpClient: &http.Client{
+			Timeout: DefaultTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Scan runs every loaded template against host:port and returns every
+// confirmed Finding.
+func (e *Engine) Scan(ctx context.Context, host string, port int) []Finding {
+	var findings []Finding
+
+	for _, t := range e.templates {
+		if !t.Info.Severity.AtLeast(e.minSeverity) {
+			continue
+		}
+
+		for _, req := range t.Requests {
+			matchedAt, body, ok := e.probe(ctx, host, port, req)
+			if !ok {
+				continue
+			}
+
+			if !evalMatchers(req, body) {
+				continue
+			}
+
+			findings = append(findings, Finding{
+				TemplateID: t.ID,
+				Severity:   t.Info.Severity,
+				Host:       host,
+				Port:       port,
+				MatchedAt:  matchedAt,
+				Extracted:  runExtractors(req.Extractors, body),
+			})
+		}
+	}
+
+	return findings
+}
+
+// probe result carries everything matchers need: the response body (or
+// raw TCP bytes) plus the status code for HTTP requests.
+type probeResult struct {
+	body       []byte
+	statusCode int
+}
+
+// probe sends req's HTTP or TCP request and returns where it was sent,
+// the response captured for matching, and whether the probe succeeded
+// (a connection/dial failure means the template doesn't apply here, not
+// a match).
+func (e *Engine) probe(ctx context.Context, host string, port int, req Request) (matchedAt string, result probeResult, ok bool) {
+	switch strings.ToLower(req.Protocol) {
+	case "tcp":
+		return e.probeTCP(ctx, host, port, req)
+	default:
+		return e.probeHTTP(ctx, host, port, req)
+	}
+}
+
+func (e *Engine) probeHTTP(ctx context.Context, host string, port int, req Request) (string, probeResult, bool) {
+	baseURL := fmt.Sprintf("http://%s:%d", host, port)
+
+	paths := req.Path
+	if len(paths) == 0 {
+		paths = []string{"{{BaseURL}}/"}
+	}
+
+	method := req.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	for _, p := range paths {
+		url := strings.ReplaceAll(p, "{{BaseURL}}", baseURL)
+
+		httpReq, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			continue
+		}
+
+		resp, err := e.httpClient.Do(httpReq)
+		if err != nil {
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+
+		return url, probeResult{body: body, statusCode: resp.StatusCode}, true
+	}
+
+	return "", probeResult{}, false
+}
+
+func (e *Engine) probeTCP(ctx context.Context, host string, port int, req Request) (string, probeResult, bool) {
+	addr := fmt.Sprintf("%s:%d", host, port)
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", probeResult{}, false
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(DefaultTimeout))
+
+	if req.Data != "" {
+		payload := decodePayload(req.Data)
+		if _, err := conn.Write(payload); err != nil {
+			return "", probeResult{}, false
+		}
+	}
+
+	buf := make([]byte, 4096)
+	n, _ := conn.Read(buf)
+
+	return addr, probeResult{body: buf[:n]}, true
+}
+
+// decodePayload turns a template's raw Data field into bytes. A "hex:"
+// prefix means the rest is hex-encoded; otherwise it's sent literally.
+func decodePayload(data string) []byte {
+	if rest, ok := strings.CutPrefix(data, "hex:"); ok {
+		if decoded, err := hex.DecodeString(strings.TrimSpace(rest)); err == nil {
+			return decoded
+		}
+	}
+	return []byte(data)
+}
+
+// evalMatchers applies req's matchers against result per
+// MatchersCondition ("and" requires all, the default "or" requires
+// any one).
+func evalMatchers(req Request, result probeResult) bool {
+	if len(req.Matchers) == 0 {
+		return true
+	}
+
+	and := strings.EqualFold(req.MatchersCondition, "and")
+
+	for _, m := range req.Matchers {
+		hit := evalMatcher(m, result)
+		if m.Negative {
+			hit = !hit
+		}
+
+		if and && !hit {
+			return false
+		}
+		if !and && hit {
+			return true
+		}
+	}
+
+	return and
+}
+
+func evalMatcher(m Matcher, result probeResult) bool {
+	switch m.Type {
+	case "status":
+		for _, s := range m.Status {
+			if s == result.statusCode {
+				return true
+			}
+		}
+		return false
+
+	case "word":
+		for _, w := range m.Words {
+			if bytes.Contains(result.body, []byte(w)) {
+				return true
+			}
+		}
+		return false
+
+	case "regex":
+		for _, pattern := range m.Regex {
+			re, err := regexp.Compile(pattern)
+			if err == nil && re.Match(result.body) {
+				return true
+			}
+		}
+		return false
+
+	case "binary":
+		for _, pattern := range m.Binary {
+			want, err := hex.DecodeString(pattern)
+			if err == nil && bytes.Contains(result.body, want) {
+				return true
+			}
+		}
+		return false
+
+	case "dsl":
+		for _, expr := range m.DSL {
+			if evalDSL(expr, result) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return false
+	}
+}
+
+// runExtractors applies req's extractors against result and returns
+// every value recovered.
+func runExtractors(extractors []Extractor, result probeResult) []string {
+	var values []string
+
+	for _, ex := range extractors {
+		switch ex.Type {
+		case "regex":
+			for _, pattern := range ex.Regex {
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					continue
+				}
+				for _, m := range re.FindAllSubmatch(result.body, -1) {
+					if len(m) > 1 {
+						values = append(values, string(m[1]))
+					} else {
+						values = append(values, string(m[0]))
+					}
+				}
+			}
+
+		case "word":
+			for _, w := range ex.Words {
+				if bytes.Contains(result.body, []byte(w)) {
+					values = append(values, w)
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// evalDSL supports exactly two shapes of expression, the common subset
+// of Nuclei's DSL this engine's checks actually need:
+//
+//	contains(body, "substring")
+//	len(body) > N   /  len(body) < N
+//
+// Anything else is treated as non-matching rather than attempting to
+// parse a general expression language.
+func evalDSL(expr string, result probeResult) bool {
+	expr = strings.TrimSpace(expr)
+
+	if rest, ok := strings.CutPrefix(expr, "contains(body,"); ok {
+		arg := strings.TrimSuffix(strings.TrimSpace(rest), ")")
+		arg = strings.Trim(arg, `"'`)
+		return bytes.Contains(result.body, []byte(arg))
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "len(body)"); ok {
+		rest = strings.TrimSpace(rest)
+		for _, op := range []string{">", "<"} {
+			if n, ok := strings.CutPrefix(rest, op); ok {
+				threshold, err := strconv.Atoi(strings.TrimSpace(n))
+				if err != nil {
+					return false
+				}
+				if op == ">" {
+					return len(result.body) > threshold
+				}
+				return len(result.body) < threshold
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,166 @@
+package vuln
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEvalMatcher(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      Matcher
+		result probeResult
+		want   bool
+	}{
+		{"status hit", Matcher{Type: "status", Status: []int{200, 301}}, probeResult{statusCode: 200}, true},
+		{"status miss", Matcher{Type: "status", Status: []int{200}}, probeResult{statusCode: 404}, false},
+		{"word hit", Matcher{Type: "word", Words: []string{"needle"}}, probeResult{body: []byte("a needle in a haystack")}, true},
+		{"word miss", Matcher{Type: "word", Words: []string{"needle"}}, probeResult{body: []byte("haystack")}, false},
+		{"regex hit", Matcher{Type: "regex", Regex: []string{`v\d+\.\d+`}}, probeResult{body: []byte("server v1.2")}, true},
+		{"binary hit", Matcher{Type: "binary", Binary: []string{"deadbeef"}}, probeResult{body: []byte{0xde, 0xad, 0xbe, 0xef}}, true},
+		{"dsl contains hit", Matcher{Type: "dsl", DSL: []string{`contains(body, "token")`}}, probeResult{body: []byte("token=abc")}, true},
+		{"negative flips a hit to a miss", Matcher{Type: "word", Words: []string{"needle"}, Negative: true}, probeResult{body: []byte("needle")}, false},
+		{"unknown type misses", Matcher{Type: "bogus"}, probeResult{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evalMatcher(tt.m, tt.result)
+			if tt.m.Negative {
+				got = !got
+			}
+			if got != tt.want {
+				t.Errorf("evalMatcher(%+v) = %v, want %v", tt.m, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalMatchers_Condition(t *testing.T) {
+	result := probeResult{statusCode: 200, body: []byte("hello")}
+
+	and := Request{
+		MatchersCondition: "and",
+		Matchers: []Matcher{
+			{Type: "status", Status: []int{200}},
+			{Type: "word", Words: []string{"missing"}},
+		},
+	}
+	if evalMatchers(and, result) {
+		t.Error("and-condition with one failing matcher should not match")
+	}
+
+	or := Request{
+		Matchers: []Matcher{
+			{Type: "status", Status: []int{404}},
+			{Type: "word", Words: []string{"hello"}},
+		},
+	}
+	if !evalMatchers(or, result) {
+		t.Error("default or-condition with one passing matcher should match")
+	}
+
+	if !evalMatchers(Request{}, result) {
+		t.Error("a request with no matchers should match (treated as always-applicable)")
+	}
+}
+
+func TestEvalDSL(t *testing.T) {
+	tests := []struct {
+		expr   string
+		result probeResult
+		want   bool
+	}{
+		{`contains(body, "abc")`, probeResult{body: []byte("xabcx")}, true},
+		{`contains(body, "zzz")`, probeResult{body: []byte("xabcx")}, false},
+		{`len(body) > 3`, probeResult{body: []byte("abcdef")}, true},
+		{`len(body) < 3`, probeResult{body: []byte("abcdef")}, false},
+		{`nonsense(body)`, probeResult{body: []byte("abcdef")}, false},
+	}
+
+	for _, tt := range tests {
+		if got := evalDSL(tt.expr, tt.result); got != tt.want {
+			t.Errorf("evalDSL(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestRunExtractors(t *testing.T) {
+	result := probeResult{body: []byte("token=abc123; session=xyz")}
+
+	extractors := []Extractor{
+		{Type: "regex", Regex: []string{`token=(\w+)`}},
+		{Type: "word", Words: []string{"session", "missing"}},
+	}
+
+	got := runExtractors(extractors, result)
+
+	want := map[string]bool{"abc123": true, "session": true}
+	if len(got) != len(want) {
+		t.Fatalf("runExtractors = %v, want %d values", got, len(want))
+	}
+	for _, v := range got {
+		if !want[v] {
+			t.Errorf("unexpected extracted value %q", v)
+		}
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	if got := string(decodePayload("plain text")); got != "plain text" {
+		t.Errorf("decodePayload(plain) = %q, want %q", got, "plain text")
+	}
+	if got := decodePayload("hex:deadbeef"); string(got) != string([]byte{0xde, 0xad, 0xbe, 0xef}) {
+		t.Errorf("decodePayload(hex:) = %x, want deadbeef", got)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `
+id: test-template
+info:
+  name: Test Template
+  severity: high
+requests:
+  - protocol: http
+    path:
+      - "{{BaseURL}}/admin"
+    matchers:
+      - type: status
+        status: [200]
+`
+	if err := os.WriteFile(filepath.Join(dir, "test.yaml"), []byte(yaml), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("not a template"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("len(templates) = %d, want 1 (non-yaml files must be skipped)", len(templates))
+	}
+	if templates[0].ID != "test-template" {
+		t.Errorf("templates[0].ID = %q, want %q", templates[0].ID, "test-template")
+	}
+	if templates[0].Info.Severity != SeverityHigh {
+		t.Errorf("templates[0].Info.Severity = %q, want %q", templates[0].Info.Severity, SeverityHigh)
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	if !SeverityHigh.AtLeast(SeverityMedium) {
+		t.Error("high should be at least medium")
+	}
+	if SeverityLow.AtLeast(SeverityHigh) {
+		t.Error("low should not be at least high")
+	}
+	if !SeverityCritical.AtLeast(SeverityCritical) {
+		t.Error("a severity should be at least itself")
+	}
+}
@@ -0,0 +1,141 @@
+// Package vuln implements a small Nuclei-style vulnerability scanning
+// engine: YAML templates describe an HTTP or TCP probe plus matchers/
+// extractors to run against its response, and Engine.Scan runs every
+// loaded Template against a host:port pair, returning confirmed
+// Findings. A handful of built-in templates (embedded, see templates.go)
+// cover common, low-risk-to-probe checks; --templates adds more from a
+// directory on disk.
+package vuln
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is a template's (or a matched Finding's) urgency, matching
+// the same vocabulary used by serviceprobe.Finding and report.Finding.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRank orders severities from least to most urgent, so
+// AtLeast(min) can compare two Severity values.
+var severityRank = map[Severity]int{
+	SeverityInfo:     0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// AtLeast reports whether s is at least as urgent as min. An unknown
+// Severity on either side is treated as info (the least urgent), so a
+// template with a typo'd severity doesn't get silently dropped either.
+func (s Severity) AtLeast(min Severity) bool {
+	return severityRank[s] >= severityRank[min]
+}
+
+// Info is a template's metadata block.
+type Info struct {
+	Name        string   `yaml:"name"`
+	Severity    Severity `yaml:"severity"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// Matcher decides whether a request's response counts as a hit. Exactly
+// one of Status/Words/Regex/Binary/DSL is meaningful, per Type.
+type Matcher struct {
+	Type   string   `yaml:"type"` // "status", "word", "regex", "binary", or "dsl"
+	Status []int    `yaml:"status,omitempty"`
+	Words  []string `yaml:"words,omitempty"`
+	Regex  []string `yaml:"regex,omitempty"`
+	// Binary patterns are hex-encoded, matched against the raw response
+	// bytes (e.g. a TCP banner).
+	Binary []string `yaml:"binary,omitempty"`
+	// DSL holds a small, fixed set of boolean expressions — see
+	// evalDSL for exactly what's supported. This is intentionally not a
+	// full expression language.
+	DSL      []string `yaml:"dsl,omitempty"`
+	Negative bool     `yaml:"negative,omitempty"`
+}
+
+// Extractor pulls a value out of a matched response.
+type Extractor struct {
+	Type  string   `yaml:"type"` // "regex" or "word"
+	Regex []string `yaml:"regex,omitempty"`
+	Words []string `yaml:"words,omitempty"`
+}
+
+// Request is one probe a Template sends.
+type Request struct {
+	// Protocol is "http" or "tcp".
+	Protocol string `yaml:"protocol"`
+
+	// HTTP fields. Path entries may reference {{BaseURL}}, replaced with
+	// http://host:port.
+	Method string   `yaml:"method,omitempty"`
+	Path   []string `yaml:"path,omitempty"`
+
+	// TCP fields. Data is the raw payload to write; a "hex:" prefix
+	// means the rest of the string is hex-encoded bytes rather than
+	// literal text.
+	Data string `yaml:"data,omitempty"`
+
+	// MatchersCondition is "and" (all matchers must hit) or "or" (the
+	// default: any one matcher hitting is enough).
+	MatchersCondition string      `yaml:"matchers-condition,omitempty"`
+	Matchers          []Matcher   `yaml:"matchers,omitempty"`
+	Extractors        []Extractor `yaml:"extractors,omitempty"`
+}
+
+// Template is one Nuclei-style YAML vulnerability check.
+type Template struct {
+	ID       string    `yaml:"id"`
+	Info     Info      `yaml:"info"`
+	Requests []Request `yaml:"requests"`
+}
+
+// LoadTemplates parses every *.yaml/*.yml file directly under dir as a
+// Template. It does not recurse, matching how --templates is expected to
+// be pointed at a flat directory of checks.
+func LoadTemplates(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("vuln: failed to read templates dir %s: %w", dir, err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("vuln: failed to read %s: %w", path, err)
+		}
+
+		var t Template
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("vuln: failed to parse %s: %w", path, err)
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,43 @@
+package vuln
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed templates/*.yaml
+var builtinFS embed.FS
+
+// Builtins returns the small set of templates shipped with pdive2
+// itself (exposed .git, a default Tomcat Manager check, and an
+// SMBGhost/CVE-2020-0796 banner probe), always run in addition to
+// whatever --templates adds.
+func Builtins() ([]Template, error) {
+	entries, err := builtinFS.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("vuln: failed to read built-in templates: %w", err)
+	}
+
+	var templates []Template
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+
+		data, err := builtinFS.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("vuln: failed to read built-in template %s: %w", entry.Name(), err)
+		}
+
+		var t Template
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("vuln: failed to parse built-in template %s: %w", entry.Name(), err)
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, nil
+}